@@ -0,0 +1,64 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.enforcementAction`
+// +kubebuilder:printcolumn:name="Violations",type=integer,JSONPath=`.status.violations`
+// +kubebuilder:printcolumn:name="Report",type=string,JSONPath=`.status.reportName`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterSecretPolicy is the Schema for the clustersecretpolicies API.
+// It is the cluster-scoped counterpart of SecretPolicy and applies to
+// Secrets in every namespace selected by its namespaceSelector.
+type ClusterSecretPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClusterSecretPolicy
+	// +required
+	Spec SecretPolicySpec `json:"spec"`
+
+	// status defines the observed state of ClusterSecretPolicy
+	// +optional
+	Status SecretPolicyStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSecretPolicyList contains a list of ClusterSecretPolicy
+type ClusterSecretPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterSecretPolicy `json:"items"`
+}
+
+func (p *ClusterSecretPolicy) GetSpec() *SecretPolicySpec     { return &p.Spec }
+func (p *ClusterSecretPolicy) GetStatus() *SecretPolicyStatus { return &p.Status }
+
+func init() {
+	SchemeBuilder.Register(&ClusterSecretPolicy{}, &ClusterSecretPolicyList{})
+}
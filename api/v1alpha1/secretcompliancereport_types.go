@@ -0,0 +1,116 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Labels set on every SecretComplianceReport so the reports of a policy can
+// be selected without knowing their names.
+const (
+	ReportPolicyKindLabel = "compliance.security.local/policy-kind"
+	ReportPolicyUIDLabel  = "compliance.security.local/policy-uid"
+)
+
+// ReportPolicyReference identifies the policy that produced a report.
+type ReportPolicyReference struct {
+	// Kind of the policy.
+	// +kubebuilder:validation:Enum=SecretPolicy;ClusterSecretPolicy
+	Kind string `json:"kind"`
+
+	// Name of the policy.
+	Name string `json:"name"`
+
+	// Namespace of the policy; empty for a ClusterSecretPolicy.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// UID of the policy.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
+	// Generation of the policy the results were computed for.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// ReportSummary aggregates the findings of a policy in one namespace. Every
+// shard of a namespace carries the same summary.
+type ReportSummary struct {
+	// Secrets is the number of Secrets in the namespace evaluated by the policy.
+	Secrets int `json:"secrets"`
+
+	// ViolatingSecrets is the number of those Secrets with at least one violation.
+	ViolatingSecrets int `json:"violatingSecrets"`
+
+	// Violations is the total number of violations.
+	Violations int `json:"violations"`
+
+	// WaivedViolations is the total number of violations suppressed by
+	// active SecretPolicyExceptions.
+	WaivedViolations int `json:"waivedViolations"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=scr
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.policy.kind`
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.policy.name`
+// +kubebuilder:printcolumn:name="Secrets",type=integer,JSONPath=`.summary.secrets`
+// +kubebuilder:printcolumn:name="Violating",type=integer,JSONPath=`.summary.violatingSecrets`
+// +kubebuilder:printcolumn:name="Violations",type=integer,JSONPath=`.summary.violations`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretComplianceReport is the Schema for the secretcompliancereports API.
+// It holds the per-Secret findings of one policy in the report's namespace
+// and is written by the operator. Namespaces with many findings are split
+// into several shards; the first is named after status.reportName of the
+// policy and the others append "-<n>".
+type SecretComplianceReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// Policy is the policy that produced the report.
+	// +required
+	Policy ReportPolicyReference `json:"policy"`
+
+	// Summary aggregates the findings in the namespace.
+	// +required
+	Summary ReportSummary `json:"summary"`
+
+	// Results lists the Secrets of this shard with violations or waived
+	// violations, ordered by name.
+	// +optional
+	Results []SecretViolationStatus `json:"results,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretComplianceReportList contains a list of SecretComplianceReport
+type SecretComplianceReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SecretComplianceReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretComplianceReport{}, &SecretComplianceReportList{})
+}
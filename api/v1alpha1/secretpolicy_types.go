@@ -17,7 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -34,13 +39,160 @@ type SecretPolicySpec struct {
 	// +optional
 	// Foo *string `json:"foo,omitempty"`
 
+	// EnforcementAction controls what happens when a Secret violates this policy.
+	// Defaults to "enforce".
+	// +kubebuilder:default=enforce
+	// +optional
+	EnforcementAction EnforcementAction `json:"enforcementAction,omitempty"`
+
+	// NamespaceSelector limits the policy to Secrets in namespaces whose labels
+	// match. An unset selector matches every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// SecretSelector limits the policy to Secrets whose labels match.
+	// An unset selector matches every Secret.
+	// +optional
+	SecretSelector *metav1.LabelSelector `json:"secretSelector,omitempty"`
+
+	// MinDenySeverity is the lowest severity that is denied at admission when the
+	// policy is enforced. Less severe violations are returned as warnings.
+	// Defaults to "low", which denies every violation.
+	// +optional
+	MinDenySeverity Severity `json:"minDenySeverity,omitempty"`
+
+	// AllowNamespacedExceptions lets SecretPolicyExceptions in the namespaces
+	// of selected Secrets waive the rules of a ClusterSecretPolicy. Without
+	// it a ClusterSecretPolicy cannot be waived, so that whoever may create
+	// exceptions in a namespace cannot switch off platform guardrails there.
+	// A SecretPolicy can always be waived by exceptions in its namespace.
+	// +optional
+	AllowNamespacedExceptions bool `json:"allowNamespacedExceptions,omitempty"`
+
 	AllowedTypes   []string `json:"allowedTypes,omitempty"`
 	DisallowedKeys []string `json:"disallowedKeys,omitempty"`
 
+	// AllowedKeyPatterns are regular expressions that data keys must match.
+	// When set, a key that matches none of them is a violation. Patterns
+	// match the whole key, e.g. "[a-z0-9_]+".
+	// +optional
+	AllowedKeyPatterns []string `json:"allowedKeyPatterns,omitempty"`
+
+	// DisallowedKeyPatterns are regular expressions for data keys that are
+	// rejected like DisallowedKeys, e.g. ".*[.](pem|p12)". Patterns match the
+	// whole key.
+	// +optional
+	DisallowedKeyPatterns []string `json:"disallowedKeyPatterns,omitempty"`
+
+	// MaxKeys is the most data keys a Secret may hold.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxKeys int `json:"maxKeys,omitempty"`
+
+	// MaxValueBytes is the largest size of a single decoded data value.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxValueBytes int64 `json:"maxValueBytes,omitempty"`
+
+	// MaxTotalBytes is the largest total size of a Secret's decoded data,
+	// keys included. The API server rejects Secrets above 1MiB regardless.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTotalBytes int64 `json:"maxTotalBytes,omitempty"`
+
+	// Rules are custom CEL expressions evaluated against every selected Secret.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Rules []CELRule `json:"rules,omitempty"`
+
 	Encryption  EncryptionSpec  `json:"encryption,omitempty"`
 	Rotation    RotationSpec    `json:"rotation,omitempty"`
 	AccessRules AccessRulesSpec `json:"accessRules,omitempty"`
 	Alerting    AlertingSpec    `json:"alerting,omitempty"`
+
+	// ContentScan inspects the decoded values of selected Secrets for
+	// credentials, high-entropy strings and weak passwords.
+	// +optional
+	ContentScan *ContentScanSpec `json:"contentScan,omitempty"`
+
+	// TLS checks the certificate and key of kubernetes.io/tls Secrets.
+	// Secrets of other types pass these rules.
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// Payload checks that typed Secrets hold a well-formed payload for their
+	// type, e.g. that a dockerconfigjson Secret parses.
+	// +optional
+	Payload *PayloadSpec `json:"payload,omitempty"`
+
+	// Unused reports Secrets that no Pod, workload, ServiceAccount, Ingress
+	// or known custom resource has referenced for a while. It is evaluated by
+	// the controller's scans only.
+	// +optional
+	Unused *UnusedSpec `json:"unused,omitempty"`
+
+	// Remediation lets the mutating webhook fix selected Secrets at admission
+	// instead of rejecting them. Audit and disabled policies never mutate.
+	// +optional
+	Remediation *RemediationSpec `json:"remediation,omitempty"`
+}
+
+// EnforcementAction describes how violations of a SecretPolicy are acted upon.
+// +kubebuilder:validation:Enum=enforce;warn;audit;disabled
+type EnforcementAction string
+
+const (
+	// EnforcementActionEnforce denies non-compliant Secrets at admission.
+	EnforcementActionEnforce EnforcementAction = "enforce"
+	// EnforcementActionWarn admits non-compliant Secrets but returns admission warnings.
+	EnforcementActionWarn EnforcementAction = "warn"
+	// EnforcementActionAudit admits non-compliant Secrets silently; violations
+	// are only recorded by the controller in the policy status.
+	EnforcementActionAudit EnforcementAction = "audit"
+	// EnforcementActionDisabled turns the policy off entirely.
+	EnforcementActionDisabled EnforcementAction = "disabled"
+)
+
+// Action returns the effective enforcement action, treating an unset value as enforce.
+func (s *SecretPolicySpec) Action() EnforcementAction {
+	if s.EnforcementAction == "" {
+		return EnforcementActionEnforce
+	}
+	return s.EnforcementAction
+}
+
+// CELRule is a custom policy rule written in the Common Expression Language.
+//
+// The expression is evaluated with a single variable, secret, shaped as:
+//
+//	secret.metadata.name, secret.metadata.namespace (string)
+//	secret.metadata.labels, secret.metadata.annotations (map(string, string))
+//	secret.type (string)
+//	secret.keys (list(string)) - the data keys
+//	secret.sizes (map(string, int)) - decoded value size per data key
+//	secret.totalSize (int) - sum of all value sizes
+//
+// A Secret complies when the expression returns true. For example:
+//
+//	secret.type != 'kubernetes.io/tls' || 'owner' in secret.metadata.labels
+type CELRule struct {
+	// Name identifies the rule. Violations are reported with the rule ID "cel.<name>".
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]([a-zA-Z0-9_-]*[a-zA-Z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Expression must evaluate to a bool; false means the Secret violates the rule.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+
+	// Message is reported when the expression evaluates to false.
+	// +kubebuilder:validation:MinLength=1
+	Message string `json:"message"`
+
+	// Severity of violations of this rule. Defaults to "medium".
+	// +optional
+	Severity Severity `json:"severity,omitempty"`
 }
 
 type EncryptionSpec struct {
@@ -56,15 +208,251 @@ type RotationSpec struct {
 }
 
 type AccessRulesSpec struct {
-	AllowedNamespaces      []string `json:"allowedNamespaces,omitempty"`
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedServiceAccounts are the only ServiceAccounts that may consume
+	// the Secret or read it through RBAC. Entries are "name" for a
+	// ServiceAccount in the Secret's namespace, "namespace/name", or
+	// "namespace/*" for every ServiceAccount of a namespace. Access is
+	// analyzed by the controller's scans only, never at admission.
+	// +optional
 	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
 }
 
 type AlertingSpec struct {
 	EnableAlerts bool   `json:"enableAlerts,omitempty"`
 	Method       string `json:"method,omitempty"` // e.g., "email", "slack"
+
+	// CredentialsRef names the Secret holding the sink configuration:
+	//
+	//	webhook: url, and optionally token (sent as a bearer token)
+	//	slack:   url of the incoming webhook
+	//	email:   host, port, from, to (comma separated), and optionally username and password
+	//
+	// A SecretPolicy may only reference a Secret in its own namespace, so the
+	// namespace may be omitted; a ClusterSecretPolicy must set it.
+	// +optional
+	CredentialsRef *corev1.SecretReference `json:"credentialsRef,omitempty"`
+
+	// RepeatInterval is how long a violation that is still present waits before
+	// it is alerted again. Defaults to 24h.
+	// +optional
+	RepeatInterval *metav1.Duration `json:"repeatInterval,omitempty"`
 }
 
+// RemediationSpec describes the changes the mutating webhook applies to
+// Secrets selected by the policy. Every change is recorded in the
+// compliance.security.local/mutations annotation of the Secret.
+type RemediationSpec struct {
+	// Labels are added to Secrets that do not already carry the label.
+	// Existing values are never overwritten.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to Secrets that do not already carry the annotation.
+	// Existing values are never overwritten.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// StampLastRotated sets the lastRotated annotation to the current time when
+	// a Secret is created or its data changes.
+	// +optional
+	StampLastRotated bool `json:"stampLastRotated,omitempty"`
+
+	// StripDisallowedKeys removes the keys listed in disallowedKeys from the Secret.
+	// +optional
+	StripDisallowedKeys bool `json:"stripDisallowedKeys,omitempty"`
+
+	// DefaultType is set on new Secrets that were created without a type, or
+	// with the implicit Opaque type when Opaque is not in allowedTypes.
+	// The type of an existing Secret is immutable and is never changed.
+	// +optional
+	DefaultType string `json:"defaultType,omitempty"`
+}
+
+// ContentScanSpec enables the content scanning rules. Each check is off
+// unless its block is set; an empty block enables it with the defaults.
+// Findings name the data key and the kind of match, never the value.
+type ContentScanSpec struct {
+	// Credentials reports values holding a well-known credential format,
+	// reported with the rule ID "content.credentials".
+	// +optional
+	Credentials *CredentialScanSpec `json:"credentials,omitempty"`
+
+	// Entropy reports values that look like random tokens, reported with the
+	// rule ID "content.entropy". Meant for policies that select Secrets used
+	// as plain configuration.
+	// +optional
+	Entropy *EntropyScanSpec `json:"entropy,omitempty"`
+
+	// WeakPasswords reports common, default and short passwords, reported
+	// with the rule ID "content.weakPassword".
+	// +optional
+	WeakPasswords *WeakPasswordScanSpec `json:"weakPasswords,omitempty"`
+
+	// IgnoreKeys lists data keys that are never scanned.
+	// +optional
+	IgnoreKeys []string `json:"ignoreKeys,omitempty"`
+}
+
+// CredentialPattern names a built-in credential format.
+// +kubebuilder:validation:Enum=aws-access-key;github-token;private-key;jwt;slack-token
+type CredentialPattern string
+
+const (
+	CredentialPatternAWSAccessKey CredentialPattern = "aws-access-key"
+	CredentialPatternGitHubToken  CredentialPattern = "github-token"
+	CredentialPatternPrivateKey   CredentialPattern = "private-key"
+	CredentialPatternJWT          CredentialPattern = "jwt"
+	CredentialPatternSlackToken   CredentialPattern = "slack-token"
+)
+
+type CredentialScanSpec struct {
+	// Patterns limits detection to the listed formats. Defaults to all of them.
+	// +optional
+	Patterns []CredentialPattern `json:"patterns,omitempty"`
+
+	// AllowedTypes lists Secret types that are expected to hold credentials
+	// and are not scanned. A private key is always allowed under the key its
+	// Secret type defines for it, i.e. tls.key and ssh-privatekey.
+	// +optional
+	AllowedTypes []string `json:"allowedTypes,omitempty"`
+}
+
+type EntropyScanSpec struct {
+	// Threshold is the Shannon entropy, in bits per character, above which
+	// a token is reported. Defaults to 4.5; random base64 approaches 6, while
+	// English text and hex strings stay below 4.5.
+	// +optional
+	Threshold *resource.Quantity `json:"threshold,omitempty"`
+
+	// MinLength is the length below which tokens are not measured. Defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinLength int `json:"minLength,omitempty"`
+}
+
+type WeakPasswordScanSpec struct {
+	// Keys are the data keys holding passwords. Defaults to keys whose name
+	// contains "password", "passwd" or "pwd", ignoring case.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// MinLength is the shortest acceptable password. Defaults to 12.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinLength int `json:"minLength,omitempty"`
+
+	// AdditionalPasswords extends the built-in list of common and default
+	// passwords, compared ignoring case.
+	// +optional
+	AdditionalPasswords []string `json:"additionalPasswords,omitempty"`
+}
+
+// TLSSpec configures the certificate checks. The leaf certificate is the
+// first certificate in tls.crt; the others are treated as its chain.
+type TLSSpec struct {
+	// MinDaysToExpiry reports certificates that expire within this many days.
+	// Expired certificates are always reported.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinDaysToExpiry int `json:"minDaysToExpiry,omitempty"`
+
+	// MinRSAKeySize is the smallest accepted RSA key, in bits. Defaults to 2048.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinRSAKeySize int `json:"minRSAKeySize,omitempty"`
+
+	// MinECDSAKeySize is the smallest accepted ECDSA key, in bits. Defaults to 256.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinECDSAKeySize int `json:"minECDSAKeySize,omitempty"`
+
+	// ForbiddenSignatureAlgorithms lists the signature algorithms, named as
+	// by Go's crypto/x509 (e.g. "SHA1-RSA"), that no certificate in tls.crt
+	// may be signed with. Self-signed roots are exempt. Defaults to the MD2,
+	// MD5 and SHA-1 based algorithms.
+	// +optional
+	ForbiddenSignatureAlgorithms []string `json:"forbiddenSignatureAlgorithms,omitempty"`
+
+	// RequiredSANs are regular expressions that must each match a DNS name
+	// or IP address of the leaf certificate. They are anchored at both ends,
+	// e.g. `.*\.example\.com`.
+	// +optional
+	RequiredSANs []string `json:"requiredSANs,omitempty"`
+
+	// VerifyChain checks that tls.crt holds a complete chain to a trusted
+	// root: one in CABundle if set, else in the ca.crt key of the Secret,
+	// else in the operator's system roots.
+	// +optional
+	VerifyChain bool `json:"verifyChain,omitempty"`
+
+	// CABundle holds the PEM encoded root certificates VerifyChain trusts.
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+}
+
+// PayloadSpec configures the payload checks of the built-in Secret types:
+//
+//	kubernetes.io/dockercfg, kubernetes.io/dockerconfigjson: the config parses
+//	kubernetes.io/basic-auth: username and password are set
+//	kubernetes.io/ssh-auth: ssh-privatekey is a parsable private key
+//	kubernetes.io/service-account-token: the referenced ServiceAccount exists
+type PayloadSpec struct {
+	// AllowedRegistries lists the registries pull Secrets may hold
+	// credentials for, as host or host:port. A leading "*." matches any
+	// subdomain. Empty allows every registry.
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// AllowedSSHKeyAlgorithms lists the algorithms ssh-auth private keys may
+	// use. Empty allows every algorithm.
+	// +optional
+	AllowedSSHKeyAlgorithms []SSHKeyAlgorithm `json:"allowedSSHKeyAlgorithms,omitempty"`
+}
+
+// UnusedSpec configures the detection of unused Secrets. The controller
+// records when it first found a Secret unreferenced in the
+// compliance.security.local/unused-since annotation and reports the Secret
+// once that is longer ago than AfterDays.
+type UnusedSpec struct {
+	// AfterDays is how long a Secret must stay unreferenced before it is
+	// reported. Defaults to 30.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AfterDays int `json:"afterDays,omitempty"`
+
+	// IgnoredTypes are Secret types that are never reported, e.g. types
+	// that are read through the API instead of being mounted.
+	// Helm release Secrets are always ignored.
+	// +optional
+	IgnoredTypes []string `json:"ignoredTypes,omitempty"`
+
+	// LabelForCleanup labels reported Secrets with
+	// compliance.security.local/cleanup=unused, and removes the label once
+	// they are referenced again, so that cleanup jobs can select them.
+	// +optional
+	LabelForCleanup bool `json:"labelForCleanup,omitempty"`
+}
+
+// SSHKeyAlgorithm names the algorithm of an SSH private key.
+// +kubebuilder:validation:Enum=rsa;ecdsa;ed25519;dsa
+type SSHKeyAlgorithm string
+
+const (
+	SSHKeyAlgorithmRSA     SSHKeyAlgorithm = "rsa"
+	SSHKeyAlgorithmECDSA   SSHKeyAlgorithm = "ecdsa"
+	SSHKeyAlgorithmEd25519 SSHKeyAlgorithm = "ed25519"
+	SSHKeyAlgorithmDSA     SSHKeyAlgorithm = "dsa"
+)
+
+// Supported values of AlertingSpec.Method.
+const (
+	AlertMethodEmail   = "email"
+	AlertMethodSlack   = "slack"
+	AlertMethodWebhook = "webhook"
+)
+
 // SecretPolicyStatus defines the observed state of SecretPolicy.
 type SecretPolicyStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -82,6 +470,10 @@ type SecretPolicyStatus struct {
 	// - "Degraded": the resource failed to reach or maintain its desired state
 	//
 
+	// ObservedGeneration is the policy generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Number of secrets evaluated by this policy
 	EnforcedSecrets int `json:"enforcedSecrets,omitempty"`
 
@@ -91,9 +483,25 @@ type SecretPolicyStatus struct {
 	// Timestamp of last successful reconciliation
 	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
 
-	// Per-secret violation summary
+	// Number of violations suppressed by active SecretPolicyExceptions
+	WaivedViolations int `json:"waivedViolations,omitempty"`
+
+	// ViolatingSecrets is the number of Secrets with at least one violation.
+	// +optional
+	ViolatingSecrets int `json:"violatingSecrets,omitempty"`
+
+	// SecretViolations lists the most severe findings, at most
+	// MaxStatusSecretViolations Secrets. The findings for every Secret are
+	// in the policy's SecretComplianceReports.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
 	SecretViolations []SecretViolationStatus `json:"secretViolations,omitempty"`
 
+	// ReportName is the name of the SecretComplianceReports holding the
+	// findings of this policy, one per namespace with Secrets in scope.
+	// +optional
+	ReportName string `json:"reportName,omitempty"`
+
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
 	// +listMapKey=type
@@ -103,11 +511,83 @@ type SecretPolicyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// MaxStatusSecretViolations caps SecretPolicyStatus.SecretViolations so the
+// policy object stays small regardless of the number of violating Secrets.
+const MaxStatusSecretViolations = 10
+
 // SecretViolationStatus holds the violation report for each secret.
 type SecretViolationStatus struct {
-	Name       string   `json:"name"`
-	Namespace  string   `json:"namespace"`
-	Violations []string `json:"violations"`
+	Name       string      `json:"name"`
+	Namespace  string      `json:"namespace"`
+	Violations []Violation `json:"violations"`
+
+	// Violations suppressed by an active SecretPolicyException, kept for auditing.
+	// +optional
+	Waived []WaivedViolation `json:"waived,omitempty"`
+}
+
+// Severity ranks how serious a violation is.
+// +kubebuilder:validation:Enum=low;medium;high;critical
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities from 1 (low) to 4 (critical). Unknown values rank 0.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Violation is a single structured finding of a policy rule against a Secret.
+type Violation struct {
+	// RuleID identifies the rule that was violated, e.g. "disallowedKeys".
+	RuleID string `json:"ruleID"`
+
+	// Severity of the finding.
+	Severity Severity `json:"severity"`
+
+	// Field is the path within the Secret that caused the finding, e.g. "data.password".
+	// +optional
+	Field string `json:"field,omitempty"`
+
+	// Message describes the finding.
+	Message string `json:"message"`
+
+	// Remediation suggests how to fix the Secret.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// String renders the violation for admission responses and events.
+func (v Violation) String() string {
+	msg := fmt.Sprintf("[%s] %s: %s", v.Severity, v.RuleID, v.Message)
+	if v.Remediation != "" {
+		msg += " (remediation: " + v.Remediation + ")"
+	}
+	return msg
+}
+
+// WaivedViolation is a violation suppressed by a SecretPolicyException.
+type WaivedViolation struct {
+	Violation `json:",inline"`
+
+	// Exception is the "<namespace>/<name>" of the waiving SecretPolicyException.
+	Exception string `json:"exception"`
 }
 
 func (s *SecretPolicyStatus) SetCondition(cond metav1.Condition) {
@@ -153,6 +633,10 @@ func (s *SecretPolicyStatus) MarkNotReady(msg string) {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.enforcementAction`
+// +kubebuilder:printcolumn:name="Violations",type=integer,JSONPath=`.status.violations`
+// +kubebuilder:printcolumn:name="Report",type=string,JSONPath=`.status.reportName`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SecretPolicy is the Schema for the secretpolicies API
 type SecretPolicy struct {
@@ -171,6 +655,24 @@ type SecretPolicy struct {
 	Status SecretPolicyStatus `json:"status,omitzero"`
 }
 
+func (p *SecretPolicy) GetSpec() *SecretPolicySpec     { return &p.Spec }
+func (p *SecretPolicy) GetStatus() *SecretPolicyStatus { return &p.Status }
+
+// PolicyObject is implemented by SecretPolicy and ClusterSecretPolicy so that
+// both kinds can share the same evaluation engine and reconcile logic.
+// +kubebuilder:object:generate=false
+type PolicyObject interface {
+	metav1.Object
+	runtime.Object
+	GetSpec() *SecretPolicySpec
+	GetStatus() *SecretPolicyStatus
+}
+
+var (
+	_ PolicyObject = &SecretPolicy{}
+	_ PolicyObject = &ClusterSecretPolicy{}
+)
+
 // +kubebuilder:object:root=true
 
 // SecretPolicyList contains a list of SecretPolicy
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyReference identifies the SecretPolicy or ClusterSecretPolicy an exception waives.
+type PolicyReference struct {
+	// Kind of the referenced policy.
+	// +kubebuilder:validation:Enum=SecretPolicy;ClusterSecretPolicy
+	// +kubebuilder:default=SecretPolicy
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced policy. A SecretPolicy must live in the same
+	// namespace as the exception.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SecretPolicyExceptionSpec defines a time-boxed waiver for Secrets in the
+// exception's namespace.
+type SecretPolicyExceptionSpec struct {
+	// PolicyRef is the policy whose rules are waived.
+	// +required
+	PolicyRef PolicyReference `json:"policyRef"`
+
+	// Rules lists the waived rule identifiers, e.g. "disallowedKeys" or
+	// "rotation". Use "*" to waive every rule of the policy.
+	// +kubebuilder:validation:MinItems=1
+	Rules []string `json:"rules"`
+
+	// SecretSelector selects the waived Secrets within the exception's namespace.
+	// +required
+	SecretSelector metav1.LabelSelector `json:"secretSelector"`
+
+	// ExpiresAt is when the waiver stops applying. Once expired, the waived
+	// violations are reported again.
+	// +required
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// Justification records why the waiver was granted.
+	// +kubebuilder:validation:MinLength=1
+	Justification string `json:"justification"`
+}
+
+// SecretPolicyExceptionStatus defines the observed state of SecretPolicyException.
+type SecretPolicyExceptionStatus struct {
+	// Standard Kubernetes status conditions. The "Active" condition turns
+	// False with reason "Expired" once expiresAt has passed.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policyRef.name`
+// +kubebuilder:printcolumn:name="Expires",type=string,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Active",type=string,JSONPath=`.status.conditions[?(@.type=="Active")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretPolicyException is the Schema for the secretpolicyexceptions API.
+// It waives selected rules of a policy for matching Secrets until it expires.
+type SecretPolicyException struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of SecretPolicyException
+	// +required
+	Spec SecretPolicyExceptionSpec `json:"spec"`
+
+	// status defines the observed state of SecretPolicyException
+	// +optional
+	Status SecretPolicyExceptionStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretPolicyExceptionList contains a list of SecretPolicyException
+type SecretPolicyExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SecretPolicyException `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretPolicyException{}, &SecretPolicyExceptionList{})
+}
@@ -21,8 +21,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -53,6 +54,16 @@ func (in *AccessRulesSpec) DeepCopy() *AccessRulesSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertingSpec) DeepCopyInto(out *AlertingSpec) {
 	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.RepeatInterval != nil {
+		in, out := &in.RepeatInterval, &out.RepeatInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertingSpec.
@@ -65,6 +76,140 @@ func (in *AlertingSpec) DeepCopy() *AlertingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELRule) DeepCopyInto(out *CELRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELRule.
+func (in *CELRule) DeepCopy() *CELRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CELRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretPolicy) DeepCopyInto(out *ClusterSecretPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretPolicy.
+func (in *ClusterSecretPolicy) DeepCopy() *ClusterSecretPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSecretPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecretPolicyList) DeepCopyInto(out *ClusterSecretPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSecretPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecretPolicyList.
+func (in *ClusterSecretPolicyList) DeepCopy() *ClusterSecretPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecretPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSecretPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentScanSpec) DeepCopyInto(out *ContentScanSpec) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(CredentialScanSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Entropy != nil {
+		in, out := &in.Entropy, &out.Entropy
+		*out = new(EntropyScanSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WeakPasswords != nil {
+		in, out := &in.WeakPasswords, &out.WeakPasswords
+		*out = new(WeakPasswordScanSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreKeys != nil {
+		in, out := &in.IgnoreKeys, &out.IgnoreKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentScanSpec.
+func (in *ContentScanSpec) DeepCopy() *ContentScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialScanSpec) DeepCopyInto(out *CredentialScanSpec) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]CredentialPattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTypes != nil {
+		in, out := &in.AllowedTypes, &out.AllowedTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialScanSpec.
+func (in *CredentialScanSpec) DeepCopy() *CredentialScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EncryptionSpec) DeepCopyInto(out *EncryptionSpec) {
 	*out = *in
@@ -80,6 +225,125 @@ func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EntropyScanSpec) DeepCopyInto(out *EntropyScanSpec) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntropyScanSpec.
+func (in *EntropyScanSpec) DeepCopy() *EntropyScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EntropyScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PayloadSpec) DeepCopyInto(out *PayloadSpec) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSSHKeyAlgorithms != nil {
+		in, out := &in.AllowedSSHKeyAlgorithms, &out.AllowedSSHKeyAlgorithms
+		*out = make([]SSHKeyAlgorithm, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PayloadSpec.
+func (in *PayloadSpec) DeepCopy() *PayloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PayloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyReference) DeepCopyInto(out *PolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyReference.
+func (in *PolicyReference) DeepCopy() *PolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationSpec) DeepCopyInto(out *RemediationSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationSpec.
+func (in *RemediationSpec) DeepCopy() *RemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportPolicyReference) DeepCopyInto(out *ReportPolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportPolicyReference.
+func (in *ReportPolicyReference) DeepCopy() *ReportPolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportPolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportSummary) DeepCopyInto(out *ReportSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportSummary.
+func (in *ReportSummary) DeepCopy() *ReportSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RotationSpec) DeepCopyInto(out *RotationSpec) {
 	*out = *in
@@ -95,6 +359,72 @@ func (in *RotationSpec) DeepCopy() *RotationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretComplianceReport) DeepCopyInto(out *SecretComplianceReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Policy = in.Policy
+	out.Summary = in.Summary
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]SecretViolationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretComplianceReport.
+func (in *SecretComplianceReport) DeepCopy() *SecretComplianceReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretComplianceReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretComplianceReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretComplianceReportList) DeepCopyInto(out *SecretComplianceReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretComplianceReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretComplianceReportList.
+func (in *SecretComplianceReportList) DeepCopy() *SecretComplianceReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretComplianceReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretComplianceReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretPolicy) DeepCopyInto(out *SecretPolicy) {
 	*out = *in
@@ -122,6 +452,110 @@ func (in *SecretPolicy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretPolicyException) DeepCopyInto(out *SecretPolicyException) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPolicyException.
+func (in *SecretPolicyException) DeepCopy() *SecretPolicyException {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretPolicyException)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretPolicyException) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretPolicyExceptionList) DeepCopyInto(out *SecretPolicyExceptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretPolicyException, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPolicyExceptionList.
+func (in *SecretPolicyExceptionList) DeepCopy() *SecretPolicyExceptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretPolicyExceptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretPolicyExceptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretPolicyExceptionSpec) DeepCopyInto(out *SecretPolicyExceptionSpec) {
+	*out = *in
+	out.PolicyRef = in.PolicyRef
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SecretSelector.DeepCopyInto(&out.SecretSelector)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPolicyExceptionSpec.
+func (in *SecretPolicyExceptionSpec) DeepCopy() *SecretPolicyExceptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretPolicyExceptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretPolicyExceptionStatus) DeepCopyInto(out *SecretPolicyExceptionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPolicyExceptionStatus.
+func (in *SecretPolicyExceptionStatus) DeepCopy() *SecretPolicyExceptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretPolicyExceptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretPolicyList) DeepCopyInto(out *SecretPolicyList) {
 	*out = *in
@@ -157,6 +591,16 @@ func (in *SecretPolicyList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretPolicySpec) DeepCopyInto(out *SecretPolicySpec) {
 	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretSelector != nil {
+		in, out := &in.SecretSelector, &out.SecretSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AllowedTypes != nil {
 		in, out := &in.AllowedTypes, &out.AllowedTypes
 		*out = make([]string, len(*in))
@@ -167,10 +611,50 @@ func (in *SecretPolicySpec) DeepCopyInto(out *SecretPolicySpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedKeyPatterns != nil {
+		in, out := &in.AllowedKeyPatterns, &out.AllowedKeyPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisallowedKeyPatterns != nil {
+		in, out := &in.DisallowedKeyPatterns, &out.DisallowedKeyPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CELRule, len(*in))
+		copy(*out, *in)
+	}
 	out.Encryption = in.Encryption
 	out.Rotation = in.Rotation
 	in.AccessRules.DeepCopyInto(&out.AccessRules)
-	out.Alerting = in.Alerting
+	in.Alerting.DeepCopyInto(&out.Alerting)
+	if in.ContentScan != nil {
+		in, out := &in.ContentScan, &out.ContentScan
+		*out = new(ContentScanSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = new(PayloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Unused != nil {
+		in, out := &in.Unused, &out.Unused
+		*out = new(UnusedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPolicySpec.
@@ -221,7 +705,12 @@ func (in *SecretViolationStatus) DeepCopyInto(out *SecretViolationStatus) {
 	*out = *in
 	if in.Violations != nil {
 		in, out := &in.Violations, &out.Violations
-		*out = make([]string, len(*in))
+		*out = make([]Violation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Waived != nil {
+		in, out := &in.Waived, &out.Waived
+		*out = make([]WaivedViolation, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -235,3 +724,104 @@ func (in *SecretViolationStatus) DeepCopy() *SecretViolationStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+	*out = *in
+	if in.ForbiddenSignatureAlgorithms != nil {
+		in, out := &in.ForbiddenSignatureAlgorithms, &out.ForbiddenSignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredSANs != nil {
+		in, out := &in.RequiredSANs, &out.RequiredSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnusedSpec) DeepCopyInto(out *UnusedSpec) {
+	*out = *in
+	if in.IgnoredTypes != nil {
+		in, out := &in.IgnoredTypes, &out.IgnoredTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnusedSpec.
+func (in *UnusedSpec) DeepCopy() *UnusedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UnusedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Violation) DeepCopyInto(out *Violation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Violation.
+func (in *Violation) DeepCopy() *Violation {
+	if in == nil {
+		return nil
+	}
+	out := new(Violation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaivedViolation) DeepCopyInto(out *WaivedViolation) {
+	*out = *in
+	out.Violation = in.Violation
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaivedViolation.
+func (in *WaivedViolation) DeepCopy() *WaivedViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(WaivedViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeakPasswordScanSpec) DeepCopyInto(out *WeakPasswordScanSpec) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalPasswords != nil {
+		in, out := &in.AdditionalPasswords, &out.AdditionalPasswords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeakPasswordScanSpec.
+func (in *WeakPasswordScanSpec) DeepCopy() *WeakPasswordScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WeakPasswordScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,18 +17,23 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,7 +41,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/alerting"
 	"github.com/Kisor-S/secret-policy-operator/internal/controller"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
 	webhookv1alpha1 "github.com/Kisor-S/secret-policy-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
@@ -63,6 +71,12 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
+	var scanOpts controller.ScanOptions
+	var policyReports bool
+	var tracingOpts tracing.Options
+	var dataHashKeySecret string
+	var workloadFailurePolicy, webhookConfiguration string
+	var maxExceptionDuration time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -80,6 +94,33 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.Int64Var(&scanOpts.PageSize, "scan-page-size", controller.DefaultScanPageSize,
+		"The number of Secrets listed per API request during a full policy scan.")
+	flag.IntVar(&scanOpts.Concurrency, "scan-concurrency", controller.DefaultScanConcurrency,
+		"The number of Secrets evaluated in parallel during a full policy scan.")
+	flag.IntVar(&scanOpts.PagesPerReconcile, "scan-pages-per-reconcile", controller.DefaultScanPagesPerReconcile,
+		"The number of pages a full policy scan processes before it yields and resumes later. "+
+			"Use -1 to scan all pages in one reconcile.")
+	flag.BoolVar(&policyReports, "policy-reports", false,
+		"If set, scan results are also written as wg-policy PolicyReports (wgpolicyk8s.io/v1alpha2). "+
+			"Requires the PolicyReport CRD.")
+	flag.StringVar(&tracingOpts.Endpoint, "otlp-endpoint", "",
+		"The host:port of an OTLP/gRPC collector to export traces to. Tracing is disabled when empty.")
+	flag.BoolVar(&tracingOpts.Insecure, "otlp-insecure", false,
+		"If set, traces are exported to the OTLP collector without TLS.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "trace-sample-ratio", 1,
+		"The fraction of traces to sample, between 0 and 1.")
+	flag.StringVar(&dataHashKeySecret, "data-hash-key-secret", "secret-policy-operator-data-hash-key",
+		"The Secret in the operator namespace holding the key rotation records are digested with. "+
+			"It is created with a random key when missing.")
+	flag.StringVar(&workloadFailurePolicy, "workload-webhook-failure-policy", controller.WorkloadFailurePolicyAuto,
+		"The failure policy of the workload webhook: "+strings.Join(controller.WorkloadFailurePolicies, ", ")+". "+
+			"Auto fails closed while an enforce policy restricts accessRules.allowedServiceAccounts.")
+	flag.StringVar(&webhookConfiguration, "validating-webhook-configuration",
+		"secret-policy-operator-validating-webhook-configuration",
+		"The ValidatingWebhookConfiguration holding the workload webhook.")
+	flag.DurationVar(&maxExceptionDuration, "max-exception-duration", webhookv1alpha1.DefaultMaxExceptionDuration,
+		"How far in the future the expiresAt of a SecretPolicyException may be set. Use 0 to disable the cap.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -172,7 +213,32 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingOpts.Endpoint != "" {
+		setupLog.Info("Exporting traces", "otlp-endpoint", tracingOpts.Endpoint, "sample-ratio", tracingOpts.SampleRatio)
+	}
+	cfg := ctrl.GetConfigOrDie()
+
+	// The key is needed by the webhooks and controllers alike, so it is loaded
+	// with a direct client before the manager starts
+	keyClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	dataHashKey, err := controller.LoadDataHashKey(context.Background(), keyClient,
+		types.NamespacedName{Namespace: operatorNamespace(), Name: dataHashKeySecret})
+	if err != nil {
+		setupLog.Error(err, "unable to load the data hash key")
+		os.Exit(1)
+	}
+	internalpolicy.SetDataHashKey(dataHashKey)
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
@@ -196,11 +262,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Shared so that alerts are deduplicated across both policy kinds.
+	// Credentials are read uncached; Secrets are only cached as metadata.
+	alerts := alerting.NewDispatcher(mgr.GetAPIReader())
+
 	if err := (&controller.SecretPolicyReconciler{
+		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
+		Scheme:        mgr.GetScheme(),
+		Alerts:        alerts,
+		Scan:          scanOpts,
+		PolicyReports: policyReports,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecretPolicy")
+		os.Exit(1)
+	}
+	if err := (&controller.ClusterSecretPolicyReconciler{
+		SecretPolicyReconciler: controller.SecretPolicyReconciler{
+			Client:        mgr.GetClient(),
+			APIReader:     mgr.GetAPIReader(),
+			Scheme:        mgr.GetScheme(),
+			Alerts:        alerts,
+			Scan:          scanOpts,
+			PolicyReports: policyReports,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecretPolicy")
+		os.Exit(1)
+	}
+	if err := (&controller.SecretPolicyExceptionReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "SecretPolicy")
+		setupLog.Error(err, "unable to create controller", "controller", "SecretPolicyException")
 		os.Exit(1)
 	}
 	// nolint:goconst
@@ -212,11 +306,40 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err := webhookv1alpha1.SetupClusterSecretPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create ClusterSecretPolicy webhook")
+			os.Exit(1)
+		}
+
+		if err := webhookv1alpha1.SetupSecretPolicyExceptionWebhookWithManager(mgr, maxExceptionDuration); err != nil {
+			setupLog.Error(err, "unable to create SecretPolicyException webhook")
+			os.Exit(1)
+		}
+
 		// Secret webhook
 		if err := webhookv1alpha1.SetupSecretWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create Secret webhook")
 			os.Exit(1)
 		}
+
+		if err := webhookv1alpha1.SetupSecretMutatingWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create Secret mutating webhook")
+			os.Exit(1)
+		}
+
+		// Pod and workload webhook for accessRules.allowedServiceAccounts
+		if err := webhookv1alpha1.SetupWorkloadWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create workload webhook")
+			os.Exit(1)
+		}
+		if err := (&controller.WorkloadWebhookReconciler{
+			Client:            mgr.GetClient(),
+			ConfigurationName: webhookConfiguration,
+			FailurePolicy:     workloadFailurePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WorkloadWebhook")
+			os.Exit(1)
+		}
 	}
 
 	// if err := webhookv1alpha1.SetupSecretPolicyWebhookWithManager(mgr); err != nil {
@@ -241,8 +364,26 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// Flush spans still buffered by the exporter
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+	cancel()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// operatorNamespace returns the namespace the operator runs in, as set by
+// the downward API, or the default install namespace.
+func operatorNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "secret-policy-operator-system"
+}
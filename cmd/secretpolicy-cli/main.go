@@ -0,0 +1,157 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command secretpolicy-cli evaluates Secret manifests against SecretPolicy,
+// ClusterSecretPolicy and SecretPolicyException manifests without a cluster.
+// Installed as kubectl-secretpolicy it also works as a kubectl plugin.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kisor-S/secret-policy-operator/internal/cli"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitViolations = 1
+	exitError      = 2
+)
+
+const usage = `Usage: secretpolicy-cli [flags] [FILE|DIR|-]...
+       secretpolicy-cli test [flags] FILE|DIR...
+
+Evaluates Secret manifests against the policies and exceptions found in the
+same inputs, using the engine of the admission webhook. Manifests are read
+from files, directories (*.yaml, *.yml, *.json) and "-" for stdin, e.g.:
+
+  kustomize build overlays/prod | secretpolicy-cli -f policies/ -f -
+
+Exits 1 when a Secret would be denied (or, with --fail-on=warn, warned) and
+2 on invalid input. The test command runs SecretPolicyTest files instead; see
+"secretpolicy-cli test -h".
+
+Flags:
+`
+
+// fileList collects repeated -f flags.
+type fileList []string
+
+func (f *fileList) String() string { return strings.Join(*f, ",") }
+
+func (f *fileList) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "test" {
+		return runTests(args[1:], stdout, stderr)
+	}
+
+	fs := flag.NewFlagSet("secretpolicy-cli", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	var files fileList
+	var output, namespace, failOn string
+	fs.Var(&files, "f", "A manifest file, directory or - for stdin. May be repeated.")
+	fs.Var(&files, "filename", "Same as -f.")
+	fs.StringVar(&output, "o", cli.FormatHuman, "Output format: "+strings.Join(cli.Formats, ", ")+".")
+	fs.StringVar(&output, "output", cli.FormatHuman, "Same as -o.")
+	fs.StringVar(&namespace, "n", "default", "Namespace of manifests that do not set one.")
+	fs.StringVar(&namespace, "namespace", "default", "Same as -n.")
+	fs.StringVar(&failOn, "fail-on", string(cli.LevelDeny),
+		"Lowest finding level that fails the run: deny or warn.")
+	args, err := parse(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	files = append(files, args...)
+
+	if !slices.Contains(cli.Formats, output) {
+		return fail(stderr, fmt.Errorf("unknown output format %q, must be one of %s", output, strings.Join(cli.Formats, ", ")))
+	}
+	if failOn != string(cli.LevelDeny) && failOn != string(cli.LevelWarn) {
+		return fail(stderr, fmt.Errorf("--fail-on must be deny or warn, got %q", failOn))
+	}
+
+	if len(files) == 0 {
+		files = fileList{cli.Stdin}
+	}
+
+	manifests := &cli.Manifests{DefaultNamespace: namespace}
+	for _, f := range files {
+		if err := manifests.LoadPath(f, stdin); err != nil {
+			return fail(stderr, err)
+		}
+	}
+	if len(manifests.Policies) == 0 {
+		return fail(stderr, fmt.Errorf("no SecretPolicy or ClusterSecretPolicy found in %s", files.String()))
+	}
+
+	report, err := cli.Evaluate(manifests, time.Now())
+	if err != nil {
+		return fail(stderr, err)
+	}
+	if err := cli.Write(stdout, output, report); err != nil {
+		return fail(stderr, err)
+	}
+
+	if report.Failed(cli.Level(failOn)) {
+		return exitViolations
+	}
+	return exitOK
+}
+
+// parse parses flags and returns the remaining arguments. Like kubectl, flags
+// may follow them.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return rest, nil
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func fail(stderr io.Writer, err error) int {
+	_, _ = fmt.Fprintln(stderr, "error:", err)
+	return exitError
+}
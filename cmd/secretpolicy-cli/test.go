@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kisor-S/secret-policy-operator/internal/cli"
+	"github.com/Kisor-S/secret-policy-operator/internal/policytest"
+)
+
+const testUsage = `Usage: secretpolicy-cli test [flags] FILE|DIR...
+
+Runs SecretPolicyTest files. Directories are searched recursively for YAML
+files of kind SecretPolicyTest. Exits 1 when a case fails and 2 on invalid
+input.
+
+Flags:
+`
+
+func runTests(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("secretpolicy-cli test", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, testUsage)
+		fs.PrintDefaults()
+	}
+
+	var output string
+	fs.StringVar(&output, "o", cli.FormatHuman, "Output format: "+strings.Join(policytest.Formats, ", ")+".")
+	fs.StringVar(&output, "output", cli.FormatHuman, "Same as -o.")
+	paths, err := parse(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+
+	if !slices.Contains(policytest.Formats, output) {
+		return fail(stderr, fmt.Errorf("unknown output format %q, must be one of %s", output, strings.Join(policytest.Formats, ", ")))
+	}
+	if len(paths) == 0 {
+		fs.Usage()
+		return exitError
+	}
+
+	files, err := policytest.Find(paths...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+	if len(files) == 0 {
+		return fail(stderr, fmt.Errorf("no SecretPolicyTest files found in %s", strings.Join(paths, ", ")))
+	}
+
+	now := time.Now()
+	var results []policytest.CaseResult
+	for _, file := range files {
+		r, err := policytest.Run(file, now)
+		if err != nil {
+			return fail(stderr, err)
+		}
+		results = append(results, r...)
+	}
+
+	if err := policytest.Write(stdout, output, results); err != nil {
+		return fail(stderr, err)
+	}
+	if policytest.Failed(results) {
+		return exitViolations
+	}
+	return exitOK
+}
@@ -0,0 +1,74 @@
+package alerting
+
+import (
+	"sync"
+	"time"
+)
+
+// Deduplicator remembers which alerts were delivered so that a violation is
+// not re-sent on every reconcile. State is kept in memory, so alerts are sent
+// once more after the operator restarts.
+type Deduplicator struct {
+	mu sync.Mutex
+	// sent maps a scope (one policy) to the last delivery time of each alert key
+	sent map[string]map[string]time.Time
+	now  func() time.Time
+}
+
+// NewDeduplicator returns an empty Deduplicator.
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{sent: map[string]map[string]time.Time{}, now: time.Now}
+}
+
+// Pending returns the alerts of current that were not delivered within the
+// repeat interval. current must be the complete set of alerts for the scope:
+// alerts that are no longer present are forgotten, so a violation that is
+// fixed and later reintroduced alerts again.
+func (d *Deduplicator) Pending(scope string, current []Alert, repeat time.Duration) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sent := d.sent[scope]
+	now := d.now()
+	active := make(map[string]bool, len(current))
+
+	var pending []Alert
+	for _, a := range current {
+		key := a.Key()
+		active[key] = true
+		if last, ok := sent[key]; ok && now.Sub(last) < repeat {
+			continue
+		}
+		pending = append(pending, a)
+	}
+
+	for key := range sent {
+		if !active[key] {
+			delete(sent, key)
+		}
+	}
+	return pending
+}
+
+// MarkSent records that the alerts were delivered.
+func (d *Deduplicator) MarkSent(scope string, alerts []Alert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sent := d.sent[scope]
+	if sent == nil {
+		sent = map[string]time.Time{}
+		d.sent[scope] = sent
+	}
+	now := d.now()
+	for _, a := range alerts {
+		sent[a.Key()] = now
+	}
+}
+
+// Forget drops all state of a scope, e.g. when its policy is deleted.
+func (d *Deduplicator) Forget(scope string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.sent, scope)
+}
@@ -0,0 +1,87 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// DefaultRepeatInterval applies when spec.alerting.repeatInterval is unset.
+const DefaultRepeatInterval = 24 * time.Hour
+
+// Dispatcher sends the alerts of a policy scan to the sink configured in the
+// policy, skipping alerts that were already delivered.
+type Dispatcher struct {
+	Client client.Reader
+	Retry  RetryPolicy
+
+	dedup *Deduplicator
+}
+
+// NewDispatcher returns a Dispatcher that reads credentials Secrets with c.
+func NewDispatcher(c client.Reader) *Dispatcher {
+	return &Dispatcher{Client: c, Retry: DefaultRetryPolicy, dedup: NewDeduplicator()}
+}
+
+// Dispatch delivers the alerts of policy that have not been sent yet and
+// returns how many were sent. alerts must hold every current violation of
+// the policy. Nothing is sent when alerting is disabled for the policy.
+func (d *Dispatcher) Dispatch(ctx context.Context, policy compliancev1alpha1.PolicyObject, alerts []Alert) (int, error) {
+	spec := policy.GetSpec().Alerting
+	scope := string(policy.GetUID())
+	if !spec.EnableAlerts {
+		d.dedup.Forget(scope)
+		return 0, nil
+	}
+
+	repeat := DefaultRepeatInterval
+	if spec.RepeatInterval != nil {
+		repeat = spec.RepeatInterval.Duration
+	}
+
+	pending := d.dedup.Pending(scope, alerts, repeat)
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	notifier, err := d.notifierFor(ctx, policy)
+	if err != nil {
+		return 0, err
+	}
+	if err := WithRetry(notifier, d.Retry).Notify(ctx, pending); err != nil {
+		return 0, fmt.Errorf("sending %d alert(s) via %s: %w", len(pending), spec.Method, err)
+	}
+
+	d.dedup.MarkSent(scope, pending)
+	return len(pending), nil
+}
+
+// Forget drops the delivery state of a deleted policy.
+func (d *Dispatcher) Forget(uid types.UID) {
+	d.dedup.Forget(string(uid))
+}
+
+func (d *Dispatcher) notifierFor(ctx context.Context, policy compliancev1alpha1.PolicyObject) (Notifier, error) {
+	spec := policy.GetSpec().Alerting
+	if spec.CredentialsRef == nil {
+		return nil, fmt.Errorf("spec.alerting.credentialsRef is required when alerts are enabled")
+	}
+
+	// A SecretPolicy can only read credentials from its own namespace
+	key := types.NamespacedName{Namespace: spec.CredentialsRef.Namespace, Name: spec.CredentialsRef.Name}
+	if ns := policy.GetNamespace(); ns != "" {
+		key.Namespace = ns
+	}
+
+	var secret corev1.Secret
+	if err := d.Client.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("reading alerting credentials %s: %w", key, err)
+	}
+	return NewNotifier(spec.Method, secret.Data)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Deduplicator", func() {
+	var (
+		d   *Deduplicator
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		d = NewDeduplicator()
+		d.now = func() time.Time { return now }
+	})
+
+	It("Should only return alerts that were not sent within the repeat interval", func() {
+		a, b := testAlert("db", "disallowedKeys"), testAlert("api", "rotation")
+		d.MarkSent("p", []Alert{a})
+
+		Expect(d.Pending("p", []Alert{a, b}, time.Hour)).To(ConsistOf(b))
+		Expect(d.Pending("other", []Alert{a}, time.Hour)).To(ConsistOf(a))
+
+		now = now.Add(2 * time.Hour)
+		Expect(d.Pending("p", []Alert{a}, time.Hour)).To(ConsistOf(a))
+	})
+
+	It("Should alert again when a resolved violation reappears", func() {
+		a := testAlert("db", "disallowedKeys")
+		d.MarkSent("p", []Alert{a})
+
+		Expect(d.Pending("p", nil, time.Hour)).To(BeEmpty())
+		Expect(d.Pending("p", []Alert{a}, time.Hour)).To(ConsistOf(a))
+	})
+})
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		ctx    context.Context
+		rec    *recorder
+		srv    *httptest.Server
+		policy *compliancev1alpha1.SecretPolicy
+		d      *Dispatcher
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rec = &recorder{}
+		srv = httptest.NewServer(rec)
+		DeferCleanup(srv.Close)
+
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline", Namespace: "apps", UID: "uid-1"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				Alerting: compliancev1alpha1.AlertingSpec{
+					EnableAlerts:   true,
+					Method:         compliancev1alpha1.AlertMethodWebhook,
+					CredentialsRef: &corev1.SecretReference{Name: "alert-sink"},
+				},
+			},
+		}
+		creds := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alert-sink", Namespace: "apps"},
+			Data:       map[string][]byte{KeyURL: []byte(srv.URL)},
+		}
+		d = NewDispatcher(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(creds).Build())
+		d.Retry = RetryPolicy{Attempts: 2, InitialBackoff: time.Millisecond}
+	})
+
+	It("Should send each violation once per repeat interval", func() {
+		alerts := []Alert{testAlert("db", "disallowedKeys")}
+
+		Expect(d.Dispatch(ctx, policy, alerts)).To(Equal(1))
+		Expect(d.Dispatch(ctx, policy, alerts)).To(Equal(0))
+		Expect(rec.requests()).To(Equal(1))
+
+		alerts = append(alerts, testAlert("api", "rotation"))
+		Expect(d.Dispatch(ctx, policy, alerts)).To(Equal(1))
+		Expect(rec.bodies[1]).To(ContainSubstring("apps/api"))
+		Expect(rec.bodies[1]).NotTo(ContainSubstring("apps/db"))
+	})
+
+	It("Should resend after a failed delivery", func() {
+		rec.statuses = []int{http.StatusBadRequest}
+		alerts := []Alert{testAlert("db", "disallowedKeys")}
+
+		_, err := d.Dispatch(ctx, policy, alerts)
+		Expect(err).To(HaveOccurred())
+		Expect(d.Dispatch(ctx, policy, alerts)).To(Equal(1))
+	})
+
+	It("Should do nothing when alerts are disabled", func() {
+		policy.Spec.Alerting.EnableAlerts = false
+		Expect(d.Dispatch(ctx, policy, []Alert{testAlert("db", "x")})).To(Equal(0))
+		Expect(rec.requests()).To(Equal(0))
+	})
+
+	It("Should only read credentials from the policy's own namespace", func() {
+		policy.Namespace = "other"
+		_, err := d.Dispatch(ctx, policy, []Alert{testAlert("db", "x")})
+		Expect(err).To(MatchError(ContainSubstring("other/alert-sink")))
+	})
+})
@@ -0,0 +1,89 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPClient is used by the HTTP sinks when no client is set.
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookNotifier posts alerts as JSON to a generic HTTP endpoint:
+//
+//	{"alerts": [{"policy": "...", "secret": "ns/name", "ruleID": "...", ...}]}
+type WebhookNotifier struct {
+	URL string
+	// Token, when set, is sent as "Authorization: Bearer <token>".
+	Token  string
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	headers := map[string]string{}
+	if n.Token != "" {
+		headers["Authorization"] = "Bearer " + n.Token
+	}
+	return postJSON(ctx, n.Client, n.URL, headers, map[string]any{"alerts": alerts})
+}
+
+// SlackNotifier posts a text message to a Slack-compatible incoming webhook.
+type SlackNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	var b strings.Builder
+	b.WriteString(summary(alerts))
+	b.WriteString(":")
+	for _, a := range alerts {
+		b.WriteString("\n• ")
+		b.WriteString(a.String())
+	}
+	return postJSON(ctx, n.Client, n.URL, nil, map[string]string{"text": b.String()})
+}
+
+func postJSON(ctx context.Context, c *http.Client, url string, headers map[string]string, payload any) error {
+	if c == nil {
+		c = defaultHTTPClient
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("POST %s: unexpected status %s", req.URL.Redacted(), resp.Status)
+	// Client errors other than timeouts and throttling will not succeed on retry
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+// Package alerting delivers policy violations to external sinks configured
+// through SecretPolicy.spec.alerting.
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Keys read from the Secret referenced by spec.alerting.credentialsRef.
+const (
+	KeyURL      = "url"
+	KeyToken    = "token"
+	KeyHost     = "host"
+	KeyPort     = "port"
+	KeyUsername = "username"
+	KeyPassword = "password"
+	KeyFrom     = "from"
+	KeyTo       = "to"
+)
+
+// Alert is a single violation of a policy by a Secret.
+type Alert struct {
+	// Policy is the policy reference, e.g. "SecretPolicy apps/baseline".
+	Policy string `json:"policy"`
+	// Secret is the namespace/name of the violating Secret.
+	Secret string `json:"secret"`
+
+	compliancev1alpha1.Violation
+}
+
+// Key identifies the alert for deduplication.
+func (a Alert) Key() string {
+	return strings.Join([]string{a.Policy, a.Secret, a.RuleID, a.Field}, "|")
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("Secret %s: %s", a.Secret, a.Violation)
+}
+
+// Notifier sends a batch of alerts to a sink.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// PermanentError marks a delivery failure that retrying cannot fix, such as
+// a rejected request or bad credentials.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err, or any error it wraps, is a PermanentError.
+func IsPermanent(err error) bool {
+	var perm *PermanentError
+	return errors.As(err, &perm)
+}
+
+// NewNotifier builds the Notifier for an alerting method from the data of the
+// credentials Secret.
+func NewNotifier(method string, data map[string][]byte) (Notifier, error) {
+	get := func(key string) string { return strings.TrimSpace(string(data[key])) }
+	require := func(keys ...string) error {
+		for _, k := range keys {
+			if get(k) == "" {
+				return fmt.Errorf("credentials Secret is missing key %q required by method %s", k, method)
+			}
+		}
+		return nil
+	}
+
+	switch method {
+	case compliancev1alpha1.AlertMethodWebhook:
+		if err := require(KeyURL); err != nil {
+			return nil, err
+		}
+		return &WebhookNotifier{URL: get(KeyURL), Token: get(KeyToken)}, nil
+
+	case compliancev1alpha1.AlertMethodSlack:
+		if err := require(KeyURL); err != nil {
+			return nil, err
+		}
+		return &SlackNotifier{URL: get(KeyURL)}, nil
+
+	case compliancev1alpha1.AlertMethodEmail:
+		if err := require(KeyHost, KeyPort, KeyFrom, KeyTo); err != nil {
+			return nil, err
+		}
+		var to []string
+		for _, addr := range strings.Split(get(KeyTo), ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		return &SMTPNotifier{
+			Addr:     net.JoinHostPort(get(KeyHost), get(KeyPort)),
+			Username: get(KeyUsername),
+			Password: get(KeyPassword),
+			From:     get(KeyFrom),
+			To:       to,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported alerting method %q", method)
+}
+
+// summary is the one-line headline shared by the chat and email sinks.
+func summary(alerts []Alert) string {
+	policies := map[string]bool{}
+	for _, a := range alerts {
+		policies[a.Policy] = true
+	}
+	if len(policies) == 1 {
+		return fmt.Sprintf("%s reported %d new violation(s)", alerts[0].Policy, len(alerts))
+	}
+	return fmt.Sprintf("%d new SecretPolicy violation(s)", len(alerts))
+}
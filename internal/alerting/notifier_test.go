@@ -0,0 +1,310 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+func testAlert(secret, rule string) Alert {
+	return Alert{
+		Policy: "SecretPolicy apps/baseline",
+		Secret: "apps/" + secret,
+		Violation: compliancev1alpha1.Violation{
+			RuleID:   rule,
+			Severity: compliancev1alpha1.SeverityHigh,
+			Field:    "data.password",
+			Message:  "key password is disallowed",
+		},
+	}
+}
+
+// recorder is an HTTP stand-in that replies with the queued status codes
+// (200 once they run out) and keeps every request body.
+type recorder struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *recorder) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+// fakeSMTP is a minimal SMTP server that accepts every message.
+type fakeSMTP struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+	// rejectRcpt makes the server refuse every recipient with a 550 reply.
+	rejectRcpt bool
+}
+
+func startFakeSMTP() *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	s := &fakeSMTP{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			if s.rejectRcpt {
+				reply("550 no such user")
+				continue
+			}
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var msg strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				msg.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+var _ = Describe("Notifiers", func() {
+	var (
+		ctx context.Context
+		rec *recorder
+		srv *httptest.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rec = &recorder{}
+		srv = httptest.NewServer(rec)
+		DeferCleanup(srv.Close)
+	})
+
+	Describe("WebhookNotifier", func() {
+		It("Should post the alerts as JSON with the bearer token", func() {
+			n := &WebhookNotifier{URL: srv.URL, Token: "s3cr3t"}
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "disallowedKeys")})).To(Succeed())
+
+			Expect(rec.headers[0].Get("Authorization")).To(Equal("Bearer s3cr3t"))
+			Expect(rec.headers[0].Get("Content-Type")).To(Equal("application/json"))
+
+			var payload struct {
+				Alerts []map[string]any `json:"alerts"`
+			}
+			Expect(json.Unmarshal([]byte(rec.bodies[0]), &payload)).To(Succeed())
+			Expect(payload.Alerts).To(ConsistOf(And(
+				HaveKeyWithValue("policy", "SecretPolicy apps/baseline"),
+				HaveKeyWithValue("secret", "apps/db"),
+				HaveKeyWithValue("ruleID", "disallowedKeys"),
+				HaveKeyWithValue("severity", "high"),
+			)))
+		})
+
+		It("Should treat client errors as permanent and server errors as transient", func() {
+			n := &WebhookNotifier{URL: srv.URL}
+
+			rec.statuses = []int{http.StatusBadRequest}
+			Expect(IsPermanent(n.Notify(ctx, []Alert{testAlert("db", "x")}))).To(BeTrue())
+
+			rec.statuses = []int{http.StatusTooManyRequests}
+			err := n.Notify(ctx, []Alert{testAlert("db", "x")})
+			Expect(err).To(HaveOccurred())
+			Expect(IsPermanent(err)).To(BeFalse())
+		})
+	})
+
+	Describe("SlackNotifier", func() {
+		It("Should post a text summary with one line per alert", func() {
+			n := &SlackNotifier{URL: srv.URL}
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "disallowedKeys"), testAlert("api", "rotation")})).To(Succeed())
+
+			var payload map[string]string
+			Expect(json.Unmarshal([]byte(rec.bodies[0]), &payload)).To(Succeed())
+			Expect(payload["text"]).To(HavePrefix("SecretPolicy apps/baseline reported 2 new violation(s):"))
+			Expect(payload["text"]).To(ContainSubstring("• Secret apps/db: [high] disallowedKeys"))
+			Expect(payload["text"]).To(ContainSubstring("• Secret apps/api: [high] rotation"))
+		})
+	})
+
+	Describe("SMTPNotifier", func() {
+		It("Should deliver one email to every recipient", func() {
+			server := startFakeSMTP()
+			DeferCleanup(server.listener.Close)
+
+			n, err := NewNotifier(compliancev1alpha1.AlertMethodEmail, map[string][]byte{
+				KeyHost: []byte("127.0.0.1"),
+				KeyPort: []byte(strings.Split(server.listener.Addr().String(), ":")[1]),
+				KeyFrom: []byte("operator@example.com"),
+				KeyTo:   []byte("sec@example.com, ops@example.com"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "disallowedKeys")})).To(Succeed())
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			Expect(server.rcpts).To(ConsistOf("<sec@example.com>", "<ops@example.com>"))
+			Expect(server.messages).To(HaveLen(1))
+			Expect(server.messages[0]).To(ContainSubstring("Subject: [secret-policy-operator] SecretPolicy apps/baseline reported 1 new violation(s)"))
+			Expect(server.messages[0]).To(ContainSubstring("Secret apps/db: [high] disallowedKeys"))
+		})
+
+		It("Should report rejected recipients as permanent errors", func() {
+			server := startFakeSMTP()
+			server.rejectRcpt = true
+			DeferCleanup(server.listener.Close)
+
+			n := &SMTPNotifier{Addr: server.listener.Addr().String(), From: "operator@example.com", To: []string{"nobody@example.com"}}
+			Expect(IsPermanent(n.Notify(ctx, []Alert{testAlert("db", "x")}))).To(BeTrue())
+		})
+
+		It("Should give up on a server that never replies once the context ends", func() {
+			// The server accepts connections but never sends its greeting
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(l.Close)
+			go func() {
+				for {
+					conn, err := l.Accept()
+					if err != nil {
+						return
+					}
+					defer func() { _ = conn.Close() }()
+				}
+			}()
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			n := &SMTPNotifier{Addr: l.Addr().String(), From: "operator@example.com", To: []string{"sec@example.com"}}
+
+			start := time.Now()
+			Expect(n.Notify(timeoutCtx, []Alert{testAlert("db", "x")})).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+
+	Describe("NewNotifier", func() {
+		It("Should reject missing keys and unknown methods", func() {
+			_, err := NewNotifier(compliancev1alpha1.AlertMethodSlack, map[string][]byte{})
+			Expect(err).To(MatchError(ContainSubstring(`missing key "url"`)))
+
+			_, err = NewNotifier("pager", map[string][]byte{KeyURL: []byte(srv.URL)})
+			Expect(err).To(MatchError(ContainSubstring("unsupported")))
+		})
+	})
+
+	Describe("WithRetry", func() {
+		policy := RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+		It("Should retry transient failures until delivery succeeds", func() {
+			rec.statuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable}
+			n := WithRetry(&WebhookNotifier{URL: srv.URL}, policy)
+
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "x")})).To(Succeed())
+			Expect(rec.requests()).To(Equal(3))
+		})
+
+		It("Should give up after the configured attempts", func() {
+			rec.statuses = []int{500, 500, 500, 500}
+			n := WithRetry(&WebhookNotifier{URL: srv.URL}, policy)
+
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "x")})).NotTo(Succeed())
+			Expect(rec.requests()).To(Equal(3))
+		})
+
+		It("Should not retry permanent failures", func() {
+			rec.statuses = []int{http.StatusUnauthorized}
+			n := WithRetry(&WebhookNotifier{URL: srv.URL}, policy)
+
+			Expect(n.Notify(ctx, []Alert{testAlert("db", "x")})).NotTo(Succeed())
+			Expect(rec.requests()).To(Equal(1))
+		})
+	})
+})
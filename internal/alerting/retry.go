@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy controls how often a failed delivery is retried.
+type RetryPolicy struct {
+	// Attempts is the total number of tries, including the first one.
+	Attempts int
+	// InitialBackoff is the wait before the first retry; it doubles after
+	// every failure up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy keeps a reconcile blocked for at most a few seconds.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:       3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     4 * time.Second,
+}
+
+// WithRetry wraps a Notifier so that transient failures are retried with
+// exponential backoff. Permanent errors are returned immediately.
+func WithRetry(n Notifier, p RetryPolicy) Notifier {
+	return &retryNotifier{next: n, policy: p}
+}
+
+type retryNotifier struct {
+	next   Notifier
+	policy RetryPolicy
+}
+
+func (r *retryNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	backoff := r.policy.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = r.next.Notify(ctx, alerts); err == nil || IsPermanent(err) || attempt >= r.policy.Attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if r.policy.MaxBackoff > 0 && backoff > r.policy.MaxBackoff {
+			backoff = r.policy.MaxBackoff
+		}
+	}
+}
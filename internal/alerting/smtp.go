@@ -0,0 +1,129 @@
+package alerting
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// defaultSMTPTimeout bounds a delivery when the context has no earlier
+// deadline.
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPNotifier sends alerts by email. STARTTLS is used when the server offers
+// it, and authentication is only attempted when Username is set; net/smtp
+// refuses to send credentials over an unencrypted connection to anything but
+// localhost.
+type SMTPNotifier struct {
+	// Addr is the host:port of the mail server.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := n.send(ctx, n.message(alerts))
+	if err != nil && ctx.Err() != nil {
+		// The connection was closed because the context ended
+		return ctx.Err()
+	}
+	// 5xx replies are permanent failures (bad recipient, auth rejected, ...)
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
+
+// send delivers msg like smtp.SendMail, which has no timeouts of its own.
+// Every dial, read and write must finish before the context's deadline or
+// defaultSMTPTimeout, and cancelling the context aborts the delivery.
+func (n *SMTPNotifier) send(ctx context.Context, msg []byte) error {
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	deadline := time.Now().Add(defaultSMTPTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", n.Username, n.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (n *SMTPNotifier) message(alerts []Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&b, "Subject: [secret-policy-operator] %s\r\n", summary(alerts))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	for _, a := range alerts {
+		fmt.Fprintf(&b, "%s: %s\r\n", a.Policy, a)
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+const policies = `
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicy
+metadata:
+  name: strict
+  namespace: apps
+spec:
+  allowedTypes: ["Opaque"]
+  disallowedKeys: ["password"]
+  minDenySeverity: high
+---
+apiVersion: compliance.security.local/v1alpha1
+kind: ClusterSecretPolicy
+metadata:
+  name: labelled
+spec:
+  enforcementAction: warn
+  allowedTypes: ["Opaque"]
+  namespaceSelector:
+    matchLabels:
+      tier: prod
+---
+apiVersion: compliance.security.local/v1alpha1
+kind: ClusterSecretPolicy
+metadata:
+  name: retired
+spec:
+  enforcementAction: disabled
+  allowedTypes: []
+`
+
+const secrets = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+  labels:
+    tier: prod
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  namespace: apps
+stringData:
+  password: hunter2
+data:
+  user: YWRtaW4=
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: tls
+    namespace: apps
+  type: kubernetes.io/tls
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: ignored
+`
+
+func load(docs ...string) *Manifests {
+	m := &Manifests{DefaultNamespace: "default"}
+	for i, doc := range docs {
+		Expect(m.Load(strings.NewReader(doc), "doc"+string(rune('0'+i)))).To(Succeed())
+	}
+	return m
+}
+
+var _ = Describe("Manifests", func() {
+	It("Should load policies, Secrets and namespace labels and skip other kinds", func() {
+		m := load(policies, secrets)
+
+		Expect(m.Policies).To(HaveLen(3))
+		Expect(m.Policies[0].GetNamespace()).To(Equal("apps"))
+		Expect(m.Policies[1].GetNamespace()).To(BeEmpty())
+
+		Expect(m.Secrets).To(HaveLen(2))
+		Expect(m.Secrets[0].Source).To(Equal("doc1"))
+		Expect(m.Secrets[0].Type).To(Equal(corev1.SecretTypeOpaque))
+		Expect(m.Secrets[0].Data).To(Equal(map[string][]byte{
+			"user":     []byte("admin"),
+			"password": []byte("hunter2"),
+		}))
+		Expect(m.Secrets[1].Name).To(Equal("tls"))
+
+		Expect(m.NamespaceLabels).To(HaveKeyWithValue("apps", map[string]string{"tier": "prod"}))
+	})
+
+	It("Should default the namespace of namespaced objects", func() {
+		m := load("apiVersion: v1\nkind: Secret\nmetadata:\n  name: web\n")
+		Expect(m.Secrets[0].Namespace).To(Equal("default"))
+	})
+
+	It("Should reject unknown policy fields", func() {
+		m := &Manifests{}
+		err := m.Load(strings.NewReader(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicy
+metadata:
+  name: typo
+spec:
+  disalowedKeys: ["password"]
+`), "typo.yaml")
+		Expect(err).To(MatchError(ContainSubstring("typo.yaml: document 1")))
+		Expect(err).To(MatchError(ContainSubstring("disalowedKeys")))
+	})
+
+	It("Should read YAML and JSON files from directories", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "policies.yaml"), []byte(policies), 0o600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, "nested"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "nested", "secret.json"),
+			[]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"web","namespace":"apps"}}`), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not yaml: ["), 0o600)).To(Succeed())
+
+		m := &Manifests{}
+		Expect(m.LoadPath(dir, nil)).To(Succeed())
+		Expect(m.Policies).To(HaveLen(3))
+		Expect(m.Secrets).To(HaveLen(1))
+		Expect(m.Secrets[0].Source).To(Equal(filepath.Join(dir, "nested", "secret.json")))
+
+		Expect(m.LoadPath(Stdin, strings.NewReader(secrets))).To(Succeed())
+		Expect(m.Secrets).To(HaveLen(3))
+		Expect(m.Secrets[1].Source).To(Equal("<stdin>"))
+	})
+})
+
+var _ = Describe("Evaluate", func() {
+	now := time.Now()
+
+	findings := func(r *Result, policy, secret string) []Finding {
+		for _, e := range r.Evaluations {
+			if e.Policy == policy && e.Secret == secret {
+				return e.Findings
+			}
+		}
+		Fail("no evaluation of " + secret + " against " + policy)
+		return nil
+	}
+
+	It("Should grade findings like the admission webhook", func() {
+		r, err := Evaluate(load(policies, secrets), now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Policies).To(Equal(2))
+		Expect(r.Secrets).To(Equal(2))
+		Expect(r.Evaluations).To(HaveLen(4))
+
+		// High severity meets minDenySeverity, medium only warns
+		Expect(findings(r, "SecretPolicy apps/strict", "apps/db")).To(ConsistOf(
+			And(HaveField("RuleID", "disallowedKeys"), HaveField("Level", LevelDeny)),
+		))
+		Expect(findings(r, "SecretPolicy apps/strict", "apps/tls")).To(ConsistOf(
+			And(HaveField("RuleID", "allowedTypes"), HaveField("Level", LevelWarn)),
+		))
+		// Selected through the labels of the Namespace manifest
+		Expect(findings(r, "ClusterSecretPolicy labelled", "apps/tls")).To(ConsistOf(HaveField("Level", LevelWarn)))
+		Expect(findings(r, "ClusterSecretPolicy labelled", "apps/db")).To(BeEmpty())
+
+		Expect(r.Failed(LevelDeny)).To(BeTrue())
+	})
+
+	It("Should waive findings covered by an exception", func() {
+		m := load(policies, secrets, `
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyException
+metadata:
+  name: legacy-db
+  namespace: apps
+spec:
+  policyRef:
+    name: strict
+  rules: ["disallowedKeys"]
+  secretSelector: {}
+  expiresAt: "2999-01-01T00:00:00Z"
+  justification: migrating
+`)
+		r, err := Evaluate(m, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(findings(r, "SecretPolicy apps/strict", "apps/db")).To(ConsistOf(
+			And(HaveField("Level", LevelWaived), HaveField("Exception", "apps/legacy-db")),
+		))
+		Expect(r.Failed(LevelDeny)).To(BeFalse())
+		Expect(r.Failed(LevelWarn)).To(BeTrue())
+	})
+
+	It("Should not modify the loaded Secrets", func() {
+		m := load(policies, secrets)
+		_, err := Evaluate(m, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Secrets[0].Annotations).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Write", func() {
+	var report *Result
+
+	BeforeEach(func() {
+		report = &Result{
+			Policies: 1,
+			Secrets:  2,
+			Evaluations: []Evaluation{
+				{Policy: "SecretPolicy apps/strict", Secret: "apps/db", Source: "secrets/db.yaml", Findings: []Finding{
+					{Violation: compliancev1alpha1.Violation{RuleID: "disallowedKeys", Severity: "high", Message: "key password is disallowed"}, Level: LevelDeny},
+					{Violation: compliancev1alpha1.Violation{RuleID: "allowedTypes", Severity: "medium", Message: "type"}, Level: LevelWaived, Exception: "apps/legacy"},
+				}},
+				{Policy: "SecretPolicy apps/strict", Secret: "apps/web", Source: "secrets/web.yaml"},
+			},
+		}
+	})
+
+	render := func(format string) []byte {
+		var buf bytes.Buffer
+		Expect(Write(&buf, format, report)).To(Succeed())
+		return buf.Bytes()
+	}
+
+	It("Should summarize human output", func() {
+		out := string(render(FormatHuman))
+		Expect(out).To(ContainSubstring("DENY"))
+		Expect(out).To(ContainSubstring("waived by apps/legacy"))
+		Expect(out).To(ContainSubstring("Checked 2 Secrets against 1 policies: 1 denied, 0 warnings, 1 waived"))
+	})
+
+	It("Should round-trip the report as JSON", func() {
+		var decoded Result
+		Expect(json.Unmarshal(render(FormatJSON), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(*report))
+	})
+
+	It("Should write a JUnit test case per evaluated Secret", func() {
+		var suites JUnitTestSuites
+		Expect(xml.Unmarshal(render(FormatJUnit), &suites)).To(Succeed())
+		Expect(suites.Tests).To(Equal(2))
+		Expect(suites.Failures).To(Equal(1))
+		Expect(suites.Suites).To(HaveLen(1))
+		Expect(suites.Suites[0].Cases[0].Failure).NotTo(BeNil())
+		Expect(suites.Suites[0].Cases[0].SystemOut).To(ContainSubstring("waived"))
+		Expect(suites.Suites[0].Cases[1].Failure).To(BeNil())
+	})
+
+	It("Should write SARIF results with suppressions for waived findings", func() {
+		var log sarif
+		Expect(json.Unmarshal(render(FormatSARIF), &log)).To(Succeed())
+		Expect(log.Version).To(Equal("2.1.0"))
+		run := log.Runs[0]
+		Expect(run.Tool.Driver.Rules).To(HaveLen(2))
+		Expect(run.Results).To(HaveLen(2))
+		Expect(run.Results[0].Level).To(Equal("error"))
+		Expect(run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI).To(Equal("secrets/db.yaml"))
+		Expect(run.Results[1].Level).To(Equal("note"))
+		Expect(run.Results[1].Suppressions).To(HaveLen(1))
+	})
+
+	It("Should reject unknown formats", func() {
+		Expect(Write(&bytes.Buffer{}, "yaml", report)).To(MatchError(ContainSubstring("unknown output format")))
+	})
+})
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// Level is the outcome of a finding.
+type Level string
+
+const (
+	// LevelDeny marks findings the admission webhook would reject.
+	LevelDeny Level = "deny"
+	// LevelWarn marks findings of warn and audit policies and findings below
+	// a policy's minDenySeverity.
+	LevelWarn Level = "warn"
+	// LevelWaived marks findings waived by a SecretPolicyException.
+	LevelWaived Level = "waived"
+)
+
+// Finding is one violation of a policy by a Secret.
+type Finding struct {
+	compliancev1alpha1.Violation `json:",inline"`
+
+	Level Level `json:"level"`
+	// Exception is the "<namespace>/<name>" of the waiving exception.
+	Exception string `json:"exception,omitempty"`
+}
+
+// Evaluation is the result of checking one Secret against one policy that
+// selects it. Secrets without findings pass.
+type Evaluation struct {
+	Policy   string    `json:"policy"`
+	Secret   string    `json:"secret"`
+	Source   string    `json:"source,omitempty"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Result is the result of evaluating a set of manifests.
+type Result struct {
+	Policies    int          `json:"policies"`
+	Secrets     int          `json:"secrets"`
+	Evaluations []Evaluation `json:"evaluations"`
+}
+
+// Count returns the number of findings at the given level.
+func (r *Result) Count(level Level) int {
+	n := 0
+	for _, e := range r.Evaluations {
+		for _, f := range e.Findings {
+			if f.Level == level {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Failed reports whether the result has findings at failOn or worse. Waived
+// findings never fail a run.
+func (r *Result) Failed(failOn Level) bool {
+	if r.Count(LevelDeny) > 0 {
+		return true
+	}
+	return failOn == LevelWarn && r.Count(LevelWarn) > 0
+}
+
+// Evaluate checks every Secret against the policies that select it, the way
+// the admission webhook would on create. Disabled policies are skipped.
+func Evaluate(m *Manifests, now time.Time) (*Result, error) {
+	result := &Result{Evaluations: []Evaluation{}}
+
+	var policies []compliancev1alpha1.PolicyObject
+	for _, p := range m.Policies {
+		if p.GetSpec().Action() != compliancev1alpha1.EnforcementActionDisabled {
+			policies = append(policies, p)
+		}
+	}
+	result.Policies = len(policies)
+	result.Secrets = len(m.Secrets)
+
+	for _, s := range m.Secrets {
+		// Never modify the loaded manifest
+		secret := s.DeepCopy()
+		// New manifests are treated as just created. Records of Secrets
+		// exported from a cluster are keyed with the operator's key, which
+		// the CLI does not have, so they are treated as just rotated
+		internalpolicy.EnsureRotationRecord(secret, now)
+
+		for _, p := range policies {
+			inScope, err := internalpolicy.SecretInScope(secret, m.NamespaceLabelsFor(secret.Namespace), p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", internalpolicy.PolicyRef(p), err)
+			}
+			if !inScope {
+				continue
+			}
+
+			found := internalpolicy.CheckSecretAgainstPolicy(secret, p)
+			found, waived := internalpolicy.ApplyExceptions(found, secret, p, m.Exceptions, now)
+
+			e := Evaluation{
+				Policy: internalpolicy.PolicyRef(p),
+				Secret: secret.Namespace + "/" + secret.Name,
+				Source: s.Source,
+			}
+			for _, v := range found {
+				e.Findings = append(e.Findings, Finding{Violation: v, Level: level(p, v)})
+			}
+			for _, w := range waived {
+				e.Findings = append(e.Findings, Finding{Violation: w.Violation, Level: LevelWaived, Exception: w.Exception})
+			}
+			result.Evaluations = append(result.Evaluations, e)
+		}
+	}
+	return result, nil
+}
+
+// level mirrors the admission webhook: only enforce policies deny, and only
+// at or above their minDenySeverity.
+func level(p compliancev1alpha1.PolicyObject, v compliancev1alpha1.Violation) Level {
+	spec := p.GetSpec()
+	if spec.Action() != compliancev1alpha1.EnforcementActionEnforce || v.Severity.Rank() < spec.MinDenySeverity.Rank() {
+		return LevelWarn
+	}
+	return LevelDeny
+}
@@ -0,0 +1,189 @@
+// Package cli evaluates Secret manifests against policy manifests without a
+// cluster. It backs the secretpolicy-cli binary and uses the same
+// internal/policy engine as the webhook and the controller.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Stdin is the file name that reads manifests from standard input.
+const Stdin = "-"
+
+// Secret is a Secret manifest and the file it was read from.
+type Secret struct {
+	*corev1.Secret
+	Source string
+}
+
+// Manifests holds the objects read from policy and Secret manifests. Objects
+// of other kinds, e.g. Deployments in kustomize output, are ignored.
+type Manifests struct {
+	Policies   []compliancev1alpha1.PolicyObject
+	Secrets    []Secret
+	Exceptions []compliancev1alpha1.SecretPolicyException
+	// NamespaceLabels holds the labels of the Namespace manifests by name,
+	// for policies that select namespaces by label.
+	NamespaceLabels map[string]map[string]string
+
+	// DefaultNamespace is assigned to namespaced objects without one,
+	// like kubectl apply does.
+	DefaultNamespace string
+}
+
+// LoadPath reads all manifests from a file, a directory (recursively, *.yaml,
+// *.yml and *.json) or Stdin.
+func (m *Manifests) LoadPath(path string, stdin io.Reader) error {
+	if path == Stdin {
+		return m.Load(stdin, "<stdin>")
+	}
+
+	return filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		// Files named explicitly are always read
+		if p != path {
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		return m.Load(f, p)
+	})
+}
+
+// Load reads a stream of YAML or JSON documents. source names the stream in
+// errors and results.
+func (m *Manifests) Load(r io.Reader, source string) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for doc := 1; ; doc++ {
+		var obj map[string]any
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%s: document %d: %w", source, doc, err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		if err := m.add(&unstructured.Unstructured{Object: obj}, source); err != nil {
+			return fmt.Errorf("%s: document %d: %w", source, doc, err)
+		}
+	}
+}
+
+func (m *Manifests) add(u *unstructured.Unstructured, source string) error {
+	gvk := u.GroupVersionKind()
+
+	if u.IsList() {
+		return u.EachListItem(func(item runtime.Object) error {
+			return m.add(item.(*unstructured.Unstructured), source)
+		})
+	}
+
+	switch gvk {
+	case corev1.SchemeGroupVersion.WithKind("Secret"):
+		secret := &corev1.Secret{}
+		if err := convert(u, secret); err != nil {
+			return err
+		}
+		m.defaultNamespace(&secret.ObjectMeta.Namespace)
+		// The API server merges stringData into data on write
+		for k, v := range secret.StringData {
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[k] = []byte(v)
+		}
+		secret.StringData = nil
+		if secret.Type == "" {
+			secret.Type = corev1.SecretTypeOpaque
+		}
+		m.Secrets = append(m.Secrets, Secret{Secret: secret, Source: source})
+
+	case corev1.SchemeGroupVersion.WithKind("Namespace"):
+		if m.NamespaceLabels == nil {
+			m.NamespaceLabels = map[string]map[string]string{}
+		}
+		m.NamespaceLabels[u.GetName()] = u.GetLabels()
+
+	case kind("SecretPolicy"):
+		policy := &compliancev1alpha1.SecretPolicy{}
+		if err := convert(u, policy); err != nil {
+			return err
+		}
+		m.defaultNamespace(&policy.Namespace)
+		m.Policies = append(m.Policies, policy)
+
+	case kind("ClusterSecretPolicy"):
+		policy := &compliancev1alpha1.ClusterSecretPolicy{}
+		if err := convert(u, policy); err != nil {
+			return err
+		}
+		policy.Namespace = ""
+		m.Policies = append(m.Policies, policy)
+
+	case kind("SecretPolicyException"):
+		exc := compliancev1alpha1.SecretPolicyException{}
+		if err := convert(u, &exc); err != nil {
+			return err
+		}
+		m.defaultNamespace(&exc.Namespace)
+		m.Exceptions = append(m.Exceptions, exc)
+	}
+	return nil
+}
+
+// NamespaceLabelsFor returns the labels of a namespace, including the
+// kubernetes.io/metadata.name label the API server sets on every namespace.
+func (m *Manifests) NamespaceLabelsFor(namespace string) map[string]string {
+	labels := map[string]string{corev1.LabelMetadataName: namespace}
+	for k, v := range m.NamespaceLabels[namespace] {
+		labels[k] = v
+	}
+	return labels
+}
+
+func (m *Manifests) defaultNamespace(namespace *string) {
+	if *namespace == "" {
+		*namespace = m.DefaultNamespace
+	}
+}
+
+func kind(kind string) schema.GroupVersionKind {
+	return compliancev1alpha1.GroupVersion.WithKind(kind)
+}
+
+// convert decodes u into obj and rejects unknown fields, so that a typo in a
+// policy fails the run instead of silently relaxing it.
+func convert(u *unstructured.Unstructured, obj any) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(u.Object, obj, true); err != nil {
+		return fmt.Errorf("%s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	return nil
+}
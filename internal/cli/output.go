@@ -0,0 +1,292 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats.
+const (
+	FormatHuman = "human"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatHuman, FormatJSON, FormatJUnit, FormatSARIF}
+
+// Write renders the result in the given format.
+func Write(w io.Writer, format string, r *Result) error {
+	switch format {
+	case FormatHuman:
+		return writeHuman(w, r)
+	case FormatJSON:
+		return writeJSON(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	case FormatSARIF:
+		return writeJSON(w, sarifLog(r))
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeHuman(w io.Writer, r *Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, e := range r.Evaluations {
+		for _, f := range e.Findings {
+			msg := f.Violation.String()
+			if f.Exception != "" {
+				msg += " (waived by " + f.Exception + ")"
+			}
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(f.Level)), e.Secret, e.Policy, msg); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nChecked %d Secrets against %d policies: %d denied, %d warnings, %d waived\n",
+		r.Secrets, r.Policies, r.Count(LevelDeny), r.Count(LevelWarn), r.Count(LevelWaived))
+	return err
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups test cases.
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a single test; it passes without a Failure.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure explains a failed test case.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes one test suite per policy and one test case per Secret it
+// selects. Denied findings fail the case; the others are listed in its output.
+func writeJUnit(w io.Writer, r *Result) error {
+	out := JUnitTestSuites{Name: "secretpolicy"}
+	index := map[string]int{}
+
+	for _, e := range r.Evaluations {
+		i, ok := index[e.Policy]
+		if !ok {
+			i = len(out.Suites)
+			index[e.Policy] = i
+			out.Suites = append(out.Suites, JUnitTestSuite{Name: e.Policy})
+		}
+
+		tc := JUnitTestCase{Name: e.Secret, Classname: e.Policy, File: e.Source}
+		var denied, other []string
+		for _, f := range e.Findings {
+			line := fmt.Sprintf("%s: %s", f.Level, f.Violation)
+			if f.Exception != "" {
+				line += " (waived by " + f.Exception + ")"
+			}
+			if f.Level == LevelDeny {
+				denied = append(denied, line)
+			} else {
+				other = append(other, line)
+			}
+		}
+		if len(denied) > 0 {
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%d violations", len(denied)),
+				Type:    string(LevelDeny),
+				Text:    strings.Join(denied, "\n"),
+			}
+			out.Suites[i].Failures++
+			out.Failures++
+		}
+		tc.SystemOut = strings.Join(other, "\n")
+
+		out.Suites[i].Cases = append(out.Suites[i].Cases, tc)
+		out.Suites[i].Tests++
+		out.Tests++
+	}
+
+	return WriteJUnit(w, out)
+}
+
+// WriteJUnit writes a JUnit XML report.
+func WriteJUnit(w io.Writer, out JUnitTestSuites) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// SARIF 2.1.0, as consumed by GitHub code scanning and other CI tools.
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/Kisor-S/secret-policy-operator"
+)
+
+type sarif struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+var sarifLevels = map[Level]string{
+	LevelDeny:   "error",
+	LevelWarn:   "warning",
+	LevelWaived: "note",
+}
+
+func sarifLog(r *Result) sarif {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "secretpolicy-cli",
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	rules := map[string]bool{}
+
+	for _, e := range r.Evaluations {
+		for _, f := range e.Findings {
+			if !rules[f.RuleID] {
+				rules[f.RuleID] = true
+				rule := sarifRule{ID: f.RuleID, ShortDescription: sarifMessage{Text: f.RuleID}}
+				if f.Remediation != "" {
+					rule.Help = &sarifMessage{Text: f.Remediation}
+				}
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+			}
+
+			loc := sarifLocation{LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: e.Secret,
+				Kind:               "resource",
+			}}}
+			if e.Source != "" && e.Source != "<stdin>" {
+				loc.PhysicalLocation = &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(e.Source)},
+				}
+			}
+
+			result := sarifResult{
+				RuleID:    f.RuleID,
+				Level:     sarifLevels[f.Level],
+				Message:   sarifMessage{Text: fmt.Sprintf("Secret %s violates %s: %s", e.Secret, e.Policy, f.Message)},
+				Locations: []sarifLocation{loc},
+				Properties: map[string]string{
+					"policy":   e.Policy,
+					"severity": string(f.Severity),
+					"field":    f.Field,
+				},
+			}
+			if f.Exception != "" {
+				result.Suppressions = []sarifSuppression{{
+					Kind:          "external",
+					Justification: "waived by SecretPolicyException " + f.Exception,
+				}}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	return sarif{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// ClusterSecretPolicyReconciler reconciles a ClusterSecretPolicy object.
+// It reuses the SecretPolicyReconciler scan logic; the only difference is
+// that a cluster-scoped policy evaluates Secrets in every namespace.
+// Changed Secrets are mapped to the ClusterSecretPolicies that select them.
+type ClusterSecretPolicyReconciler struct {
+	SecretPolicyReconciler
+}
+
+// +kubebuilder:rbac:groups=compliance.security.local,resources=clustersecretpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=compliance.security.local,resources=clustersecretpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compliance.security.local,resources=clustersecretpolicies/finalizers,verbs=update
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterSecretPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor("clustersecretpolicy-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&compliancev1alpha1.ClusterSecretPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToPolicies("ClusterSecretPolicy")),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&compliancev1alpha1.SecretPolicyException{},
+			handler.EnqueueRequestsFromMapFunc(r.exceptionToPolicy("ClusterSecretPolicy")),
+		).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToPolicies("ClusterSecretPolicy")),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Named("clustersecretpolicy").
+		Complete(r)
+}
+
+// Reconcile scans all Secrets against a ClusterSecretPolicy and updates its status.
+func (r *ClusterSecretPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var policy compliancev1alpha1.ClusterSecretPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.pending.forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	logger.Info("Reconciling ClusterSecretPolicy", "policy", req.Name)
+	return r.reconcileSecretPolicy(ctx, &policy)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DataHashKeyField is the key of the data hash key Secret that holds the key.
+const DataHashKeyField = "key"
+
+// dataHashKeySize is the size of generated keys, and the minimum size of
+// keys supplied by hand.
+const dataHashKeySize = 32
+
+// LoadDataHashKey returns the key the rotation records of Secrets are
+// digested with. The key is kept in the Secret named by key, which is
+// created with a random key on first start. Replicas that race to create it
+// all use the key that was stored first.
+func LoadDataHashKey(ctx context.Context, c client.Client, key types.NamespacedName) ([]byte, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, key, &secret)
+	if apierrors.IsNotFound(err) {
+		generated := make([]byte, dataHashKeySize)
+		if _, err := rand.Read(generated); err != nil {
+			return nil, err
+		}
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "secret-policy-operator"},
+			},
+			Type:      corev1.SecretTypeOpaque,
+			Immutable: ptr.To(true),
+			Data:      map[string][]byte{DataHashKeyField: generated},
+		}
+		err = c.Create(ctx, &secret)
+		if apierrors.IsAlreadyExists(err) {
+			err = c.Get(ctx, key, &secret)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading data hash key from Secret %s: %w", key, err)
+	}
+
+	if len(secret.Data[DataHashKeyField]) < dataHashKeySize {
+		return nil, fmt.Errorf("data hash key Secret %s must hold at least %d bytes in %q", key, dataHashKeySize, DataHashKeyField)
+	}
+	return secret.Data[DataHashKeyField], nil
+}
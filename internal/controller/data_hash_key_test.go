@@ -0,0 +1,77 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("LoadDataHashKey", func() {
+	key := types.NamespacedName{Namespace: "secret-policy-operator-system", Name: "data-hash-key"}
+
+	It("generates a key on first start and reuses it", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+		generated, err := LoadDataHashKey(context.Background(), c, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generated).To(HaveLen(dataHashKeySize))
+
+		var secret corev1.Secret
+		Expect(c.Get(context.Background(), key, &secret)).To(Succeed())
+		Expect(secret.Immutable).To(HaveValue(BeTrue()))
+
+		loaded, err := LoadDataHashKey(context.Background(), c, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(generated))
+	})
+
+	It("uses the key of a replica that created the Secret first", func() {
+		stored := []byte("0123456789abcdef0123456789abcdef")
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				winner := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+					Data:       map[string][]byte{DataHashKeyField: stored},
+				}
+				Expect(c.Create(ctx, winner)).To(Succeed())
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+		Expect(LoadDataHashKey(context.Background(), c, key)).To(Equal(stored))
+	})
+
+	It("rejects short keys", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string][]byte{DataHashKeyField: []byte("short")},
+		}).Build()
+
+		_, err := LoadDataHashKey(context.Background(), c, key)
+		Expect(err).To(MatchError(ContainSubstring("at least 32 bytes")))
+	})
+})
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// pendingScans tracks, per policy, which Secrets changed since the policy was
+// last reconciled and the results of the last scan. Together they let a
+// Secret event re-evaluate just that Secret instead of rescanning every
+// Secret the policy selects. The zero value is ready to use.
+//
+// It also holds the progress of paginated full scans that yield between
+// reconciles.
+//
+// The state lives in memory; after a restart the first reconcile of each
+// policy is a full scan.
+type pendingScans struct {
+	mu       sync.Mutex
+	secrets  map[types.NamespacedName]map[types.NamespacedName]struct{}
+	full     map[types.NamespacedName]bool
+	results  map[types.NamespacedName]*scanResults
+	progress map[types.NamespacedName]*scanProgress
+}
+
+// scanResults holds which Secrets a policy selects and the findings for
+// those that have any.
+type scanResults struct {
+	scope    map[types.NamespacedName]struct{}
+	findings map[types.NamespacedName]compliancev1alpha1.SecretViolationStatus
+}
+
+func newScanResults() *scanResults {
+	return &scanResults{
+		scope:    map[types.NamespacedName]struct{}{},
+		findings: map[types.NamespacedName]compliancev1alpha1.SecretViolationStatus{},
+	}
+}
+
+// set records the outcome of evaluating secret. entry is nil when the Secret
+// is out of scope or has no findings.
+func (s *scanResults) set(secret types.NamespacedName, inScope bool, entry *compliancev1alpha1.SecretViolationStatus) {
+	if inScope {
+		s.scope[secret] = struct{}{}
+	} else {
+		delete(s.scope, secret)
+	}
+	if entry != nil {
+		s.findings[secret] = *entry
+	} else {
+		delete(s.findings, secret)
+	}
+}
+
+// scanProgress is the partial result of a full scan that has not yet listed
+// every page.
+type scanProgress struct {
+	*scanResults
+
+	// generation is the policy generation the scan evaluates.
+	generation int64
+	// continueToken resumes the Secret list after the last processed page.
+	continueToken string
+}
+
+// init allocates the maps on first use so the zero value is ready to use.
+// Callers must hold mu.
+func (p *pendingScans) init() {
+	if p.secrets == nil {
+		p.secrets = map[types.NamespacedName]map[types.NamespacedName]struct{}{}
+		p.full = map[types.NamespacedName]bool{}
+		p.results = map[types.NamespacedName]*scanResults{}
+		p.progress = map[types.NamespacedName]*scanProgress{}
+	}
+}
+
+// addSecret records that secret changed and must be re-evaluated by policy.
+func (p *pendingScans) addSecret(policy, secret types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	if p.secrets[policy] == nil {
+		p.secrets[policy] = map[types.NamespacedName]struct{}{}
+	}
+	p.secrets[policy][secret] = struct{}{}
+}
+
+// requestFull forces the next reconcile of policy to rescan every Secret.
+func (p *pendingScans) requestFull(policy types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	p.full[policy] = true
+}
+
+// take returns and clears the pending work of policy. full is true when a
+// full scan was requested or the in-scope set of the policy is unknown. An
+// explicit request discards a partial scan, since pages already processed
+// may be stale.
+func (p *pendingScans) take(policy types.NamespacedName) (secrets []types.NamespacedName, full bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	for s := range p.secrets[policy] {
+		secrets = append(secrets, s)
+	}
+	_, known := p.results[policy]
+	full = p.full[policy] || !known
+	if p.full[policy] {
+		delete(p.progress, policy)
+	}
+
+	delete(p.secrets, policy)
+	delete(p.full, policy)
+	return secrets, full
+}
+
+// setResults replaces the results of policy after a full scan.
+func (p *pendingScans) setResults(policy types.NamespacedName, results *scanResults) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	p.results[policy] = results
+}
+
+// updateResult records the outcome of re-evaluating a single Secret and
+// returns the new number of Secrets in scope of policy.
+func (p *pendingScans) updateResult(policy, secret types.NamespacedName, inScope bool, entry *compliancev1alpha1.SecretViolationStatus) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	results := p.results[policy]
+	if results == nil {
+		results = newScanResults()
+		p.results[policy] = results
+	}
+	results.set(secret, inScope, entry)
+	return len(results.scope)
+}
+
+// snapshot returns the names of the Secrets in scope of policy per namespace
+// and the findings, both ordered by namespace and name.
+func (p *pendingScans) snapshot(policy types.NamespacedName) (map[string][]string, []compliancev1alpha1.SecretViolationStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	scope := map[string][]string{}
+	var findings []compliancev1alpha1.SecretViolationStatus
+	if results := p.results[policy]; results != nil {
+		for secret := range results.scope {
+			scope[secret.Namespace] = append(scope[secret.Namespace], secret.Name)
+		}
+		for _, entry := range results.findings {
+			findings = append(findings, entry)
+		}
+	}
+
+	for _, names := range scope {
+		sort.Strings(names)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Namespace != findings[j].Namespace {
+			return findings[i].Namespace < findings[j].Namespace
+		}
+		return findings[i].Name < findings[j].Name
+	})
+	return scope, findings
+}
+
+// inProgress returns the partial full scan of policy, or nil if none is
+// in progress.
+func (p *pendingScans) inProgress(policy types.NamespacedName) *scanProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+	return p.progress[policy]
+}
+
+// setScanProgress stores the partial full scan of policy; nil clears it.
+func (p *pendingScans) setScanProgress(policy types.NamespacedName, progress *scanProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	if progress == nil {
+		delete(p.progress, policy)
+		return
+	}
+	p.progress[policy] = progress
+}
+
+// forget drops all state of policy, e.g. when it is deleted or disabled.
+func (p *pendingScans) forget(policy types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.init()
+
+	delete(p.secrets, policy)
+	delete(p.full, policy)
+	delete(p.results, policy)
+	delete(p.progress, policy)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// PolicyReportGVK is the wg-policy Policy Report API the controller writes
+// to when PolicyReports is enabled. The API is not vendored; reports are
+// written as unstructured objects.
+var PolicyReportGVK = schema.GroupVersionKind{Group: "wgpolicyk8s.io", Version: "v1alpha2", Kind: "PolicyReport"}
+
+// Values of policyReportResult.Result.
+const (
+	policyResultPass = "pass"
+	policyResultFail = "fail"
+	policyResultWarn = "warn"
+	policyResultSkip = "skip"
+)
+
+// policyReportSource is reported as the source of every result.
+const policyReportSource = "secret-policy-operator"
+
+// +kubebuilder:rbac:groups=wgpolicyk8s.io,resources=policyreports,verbs=get;list;watch;create;update;patch;delete
+
+// policyReportResult mirrors PolicyReportResult of wgpolicyk8s.io/v1alpha2.
+type policyReportResult struct {
+	Policy     string                   `json:"policy"`
+	Rule       string                   `json:"rule,omitempty"`
+	Result     string                   `json:"result"`
+	Severity   string                   `json:"severity,omitempty"`
+	Message    string                   `json:"message,omitempty"`
+	Source     string                   `json:"source"`
+	Category   string                   `json:"category,omitempty"`
+	Resources  []corev1.ObjectReference `json:"resources"`
+	Properties map[string]string        `json:"properties,omitempty"`
+}
+
+// policyReportSummary mirrors PolicyReportSummary of wgpolicyk8s.io/v1alpha2.
+type policyReportSummary struct {
+	Pass  int `json:"pass"`
+	Fail  int `json:"fail"`
+	Warn  int `json:"warn"`
+	Error int `json:"error"`
+	Skip  int `json:"skip"`
+}
+
+func (s *policyReportSummary) add(result string) {
+	switch result {
+	case policyResultPass:
+		s.Pass++
+	case policyResultFail:
+		s.Fail++
+	case policyResultWarn:
+		s.Warn++
+	case policyResultSkip:
+		s.Skip++
+	}
+}
+
+// policyReportResults turns the outcome of evaluating one Secret into one
+// result per active rule and finding: fail for violations the policy
+// enforces, warn for violations it only reports, skip for waived violations
+// and pass for rules without findings.
+func policyReportResults(
+	policy compliancev1alpha1.PolicyObject,
+	rules []string,
+	namespace, name string,
+	entry *compliancev1alpha1.SecretViolationStatus,
+) []policyReportResult {
+	spec := policy.GetSpec()
+	policyName := policy.GetName()
+	if policy.GetNamespace() != "" {
+		policyName = policy.GetNamespace() + "/" + policyName
+	}
+
+	base := policyReportResult{
+		Policy:    policyName,
+		Source:    policyReportSource,
+		Category:  "Secret Governance",
+		Resources: []corev1.ObjectReference{{APIVersion: "v1", Kind: "Secret", Namespace: namespace, Name: name}},
+	}
+
+	violated := map[string][]policyReportResult{}
+	if entry != nil {
+		for _, v := range entry.Violations {
+			result := policyResultWarn
+			if spec.Action() == compliancev1alpha1.EnforcementActionEnforce && v.Severity.Rank() >= spec.MinDenySeverity.Rank() {
+				result = policyResultFail
+			}
+			violated[v.RuleID] = append(violated[v.RuleID], withViolation(base, v, result, v.Message))
+		}
+		for _, w := range entry.Waived {
+			msg := fmt.Sprintf("%s (waived by SecretPolicyException %s)", w.Message, w.Exception)
+			violated[w.RuleID] = append(violated[w.RuleID], withViolation(base, w.Violation, policyResultSkip, msg))
+		}
+	}
+
+	var results []policyReportResult
+	for _, rule := range rules {
+		if found, ok := violated[rule]; ok {
+			results = append(results, found...)
+			delete(violated, rule)
+			continue
+		}
+		pass := base
+		pass.Rule = rule
+		pass.Result = policyResultPass
+		pass.Message = "rule passed"
+		results = append(results, pass)
+	}
+
+	// Report findings of rules that are not in the list as well
+	for _, rule := range sortedRuleIDs(violated) {
+		results = append(results, violated[rule]...)
+	}
+	return results
+}
+
+func withViolation(base policyReportResult, v compliancev1alpha1.Violation, result, message string) policyReportResult {
+	base.Rule = v.RuleID
+	base.Result = result
+	base.Severity = string(v.Severity)
+	base.Message = message
+	if v.Field != "" || v.Remediation != "" {
+		base.Properties = map[string]string{}
+		if v.Field != "" {
+			base.Properties["field"] = v.Field
+		}
+		if v.Remediation != "" {
+			base.Properties["remediation"] = v.Remediation
+		}
+	}
+	return base
+}
+
+func sortedRuleIDs(m map[string][]policyReportResult) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// syncPolicyReports mirrors the policy's results into wg-policy
+// PolicyReports, one or more per namespace, named like the policy's
+// SecretComplianceReports. When namespaces is not nil only reports in those
+// namespaces are touched. Clusters without the PolicyReport CRD are skipped.
+func (r *SecretPolicyReconciler) syncPolicyReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	scope map[string][]string,
+	findings []compliancev1alpha1.SecretViolationStatus,
+	namespaces map[string]bool,
+) error {
+	err := r.writePolicyReports(ctx, policy, scope, findings, namespaces)
+	if meta.IsNoMatchError(err) {
+		log.FromContext(ctx).Info("PolicyReport API is not installed, skipping policy reports")
+		return nil
+	}
+	return err
+}
+
+func (r *SecretPolicyReconciler) writePolicyReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	scope map[string][]string,
+	findings []compliancev1alpha1.SecretViolationStatus,
+	namespaces map[string]bool,
+) error {
+	base := reportName(policy)
+	rules := internalpolicy.ActiveRules(policy, internalpolicy.WithAccessAnalysis())
+
+	byName := map[client.ObjectKey]*compliancev1alpha1.SecretViolationStatus{}
+	for i := range findings {
+		byName[client.ObjectKey{Namespace: findings[i].Namespace, Name: findings[i].Name}] = &findings[i]
+	}
+
+	wanted := map[client.ObjectKey]bool{}
+	for ns, secrets := range scope {
+		if namespaces != nil && !namespaces[ns] {
+			continue
+		}
+
+		for i := 0; i*reportShardSize < len(secrets); i++ {
+			var content struct {
+				Summary policyReportSummary  `json:"summary"`
+				Results []policyReportResult `json:"results,omitempty"`
+			}
+			for _, name := range secrets[i*reportShardSize : min((i+1)*reportShardSize, len(secrets))] {
+				entry := byName[client.ObjectKey{Namespace: ns, Name: name}]
+				for _, result := range policyReportResults(policy, rules, ns, name, entry) {
+					content.Summary.add(result.Result)
+					content.Results = append(content.Results, result)
+				}
+			}
+			fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&content)
+			if err != nil {
+				return err
+			}
+
+			report := &unstructured.Unstructured{}
+			report.SetGroupVersionKind(PolicyReportGVK)
+			report.SetName(shardName(base, i))
+			report.SetNamespace(ns)
+			wanted[client.ObjectKeyFromObject(report)] = true
+
+			if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, report, func() error {
+				labels := report.GetLabels()
+				if labels == nil {
+					labels = map[string]string{}
+				}
+				labels["app.kubernetes.io/managed-by"] = policyReportSource
+				labels[compliancev1alpha1.ReportPolicyKindLabel] = internalpolicy.PolicyKind(policy)
+				labels[compliancev1alpha1.ReportPolicyUIDLabel] = string(policy.GetUID())
+				report.SetLabels(labels)
+
+				report.Object["summary"] = fields["summary"]
+				if results, ok := fields["results"]; ok {
+					report.Object["results"] = results
+				} else {
+					delete(report.Object, "results")
+				}
+				return controllerutil.SetControllerReference(policy, report, r.Scheme)
+			}); err != nil {
+				return fmt.Errorf("writing policy report %s/%s: %w", ns, report.GetName(), err)
+			}
+		}
+	}
+
+	return r.deletePolicyReports(ctx, policy, func(report *unstructured.Unstructured) bool {
+		if namespaces != nil && !namespaces[report.GetNamespace()] {
+			return false
+		}
+		return !wanted[client.ObjectKeyFromObject(report)]
+	})
+}
+
+// deletePolicyReports deletes the PolicyReports of policy for which stale
+// returns true. Clusters without the PolicyReport CRD have nothing to delete.
+func (r *SecretPolicyReconciler) deletePolicyReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	stale func(*unstructured.Unstructured) bool,
+) error {
+	var reports unstructured.UnstructuredList
+	reports.SetGroupVersionKind(PolicyReportGVK.GroupVersion().WithKind(PolicyReportGVK.Kind + "List"))
+	if err := r.List(ctx, &reports,
+		client.InNamespace(policy.GetNamespace()),
+		client.MatchingLabels{compliancev1alpha1.ReportPolicyUIDLabel: string(policy.GetUID())},
+	); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	for i := range reports.Items {
+		if !stale(&reports.Items[i]) {
+			continue
+		}
+		if err := r.Delete(ctx, &reports.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting policy report %s/%s: %w", reports.Items[i].GetNamespace(), reports.Items[i].GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+var _ = Describe("PolicyReport results", func() {
+	var policy *compliancev1alpha1.SecretPolicy
+
+	BeforeEach(func() {
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-policy", Namespace: "team-a"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys: []string{"password"},
+			},
+		}
+	})
+
+	resultsByRule := func(entry *compliancev1alpha1.SecretViolationStatus) map[string]string {
+		byRule := map[string]string{}
+		for _, r := range policyReportResults(policy, internalpolicy.ActiveRules(policy), "team-a", "db", entry) {
+			Expect(r.Policy).To(Equal("team-a/db-policy"))
+			Expect(r.Resources).To(ConsistOf(corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "team-a", Name: "db"}))
+			byRule[r.Rule] = r.Result
+		}
+		return byRule
+	}
+
+	violation := func(rule string, severity compliancev1alpha1.Severity) compliancev1alpha1.Violation {
+		return compliancev1alpha1.Violation{RuleID: rule, Severity: severity, Message: "bad"}
+	}
+
+	It("passes every active rule of a compliant Secret", func() {
+		Expect(resultsByRule(nil)).To(Equal(map[string]string{
+			internalpolicy.RuleAllowedTypes:   policyResultPass,
+			internalpolicy.RuleDisallowedKeys: policyResultPass,
+		}))
+	})
+
+	It("fails enforced violations and skips waived ones", func() {
+		entry := &compliancev1alpha1.SecretViolationStatus{
+			Violations: []compliancev1alpha1.Violation{violation(internalpolicy.RuleDisallowedKeys, compliancev1alpha1.SeverityHigh)},
+			Waived: []compliancev1alpha1.WaivedViolation{{
+				Violation: violation(internalpolicy.RuleAllowedTypes, compliancev1alpha1.SeverityMedium),
+				Exception: "legacy",
+			}},
+		}
+		Expect(resultsByRule(entry)).To(Equal(map[string]string{
+			internalpolicy.RuleAllowedTypes:   policyResultSkip,
+			internalpolicy.RuleDisallowedKeys: policyResultFail,
+		}))
+	})
+
+	It("warns about violations the policy does not deny", func() {
+		entry := &compliancev1alpha1.SecretViolationStatus{
+			Violations: []compliancev1alpha1.Violation{violation(internalpolicy.RuleAllowedTypes, compliancev1alpha1.SeverityMedium)},
+		}
+
+		policy.Spec.MinDenySeverity = compliancev1alpha1.SeverityHigh
+		Expect(resultsByRule(entry)).To(HaveKeyWithValue(internalpolicy.RuleAllowedTypes, policyResultWarn))
+
+		policy.Spec.MinDenySeverity = ""
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionAudit
+		Expect(resultsByRule(entry)).To(HaveKeyWithValue(internalpolicy.RuleAllowedTypes, policyResultWarn))
+	})
+})
+
+var _ = Describe("PolicyReports", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx context.Context
+		c   client.Client
+		r   *SecretPolicyReconciler
+		key = types.NamespacedName{Namespace: namespace, Name: "db-policy"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&compliancev1alpha1.SecretPolicy{}).
+			WithObjects(
+				&compliancev1alpha1.SecretPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace, Generation: 1, UID: "policy-uid"},
+					Spec: compliancev1alpha1.SecretPolicySpec{
+						AllowedTypes: []string{string(corev1.SecretTypeOpaque)},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "ok", Namespace: namespace},
+					Type:       corev1.SecretTypeOpaque,
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "bad", Namespace: namespace},
+					Type:       corev1.SecretTypeBasicAuth,
+				},
+			).
+			Build()
+		r = &SecretPolicyReconciler{
+			Client:        c,
+			Scheme:        scheme.Scheme,
+			Recorder:      record.NewFakeRecorder(100),
+			PolicyReports: true,
+		}
+	})
+
+	It("writes a PolicyReport per namespace from the scan results", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := &unstructured.Unstructured{}
+		report.SetGroupVersionKind(PolicyReportGVK)
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "secretpolicy-db-policy"}, report)).To(Succeed())
+		Expect(report.GetLabels()).To(HaveKeyWithValue(compliancev1alpha1.ReportPolicyUIDLabel, "policy-uid"))
+
+		summary, _, _ := unstructured.NestedMap(report.Object, "summary")
+		Expect(summary).To(HaveKeyWithValue("pass", BeEquivalentTo(1)))
+		Expect(summary).To(HaveKeyWithValue("fail", BeEquivalentTo(1)))
+
+		results, _, _ := unstructured.NestedSlice(report.Object, "results")
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(HaveKeyWithValue("result", "fail"))
+		Expect(results[0]).To(HaveKeyWithValue("source", policyReportSource))
+	})
+
+	It("deletes the PolicyReports of a disabled policy", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionDisabled
+		Expect(c.Update(ctx, &policy)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var reports unstructured.UnstructuredList
+		reports.SetGroupVersionKind(PolicyReportGVK.GroupVersion().WithKind("PolicyReportList"))
+		Expect(c.List(ctx, &reports, client.InNamespace(namespace))).To(Succeed())
+		Expect(reports.Items).To(BeEmpty())
+	})
+})
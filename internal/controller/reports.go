@@ -0,0 +1,194 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// reportShardSize is the number of Secrets per SecretComplianceReport. It
+// keeps a single report well below the etcd object size limit even when
+// every Secret carries several violations.
+const reportShardSize = 250
+
+// +kubebuilder:rbac:groups=compliance.security.local,resources=secretcompliancereports,verbs=get;list;watch;create;update;patch;delete
+
+// reportName returns the name of the first SecretComplianceReport shard of
+// policy in each namespace. The kind is part of the name so that a
+// SecretPolicy and a ClusterSecretPolicy of the same name do not collide.
+func reportName(policy compliancev1alpha1.PolicyObject) string {
+	name := strings.ToLower(internalpolicy.PolicyKind(policy)) + "-" + policy.GetName()
+
+	// Leave room for the shard suffix
+	const maxLen = validation.DNS1123SubdomainMaxLength - 6
+	if len(name) <= maxLen {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprintf("%s-%08x", strings.TrimRight(name[:maxLen-9], ".-"), h.Sum32())
+}
+
+// shardName returns the name of the i-th report shard.
+func shardName(base string, i int) string {
+	if i == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, i)
+}
+
+// topSecretViolations returns at most n findings with violations, the most
+// severe first.
+func topSecretViolations(findings []compliancev1alpha1.SecretViolationStatus, n int) []compliancev1alpha1.SecretViolationStatus {
+	var top []compliancev1alpha1.SecretViolationStatus
+	for _, sv := range findings {
+		if len(sv.Violations) > 0 {
+			top = append(top, sv)
+		}
+	}
+
+	worst := func(sv compliancev1alpha1.SecretViolationStatus) int {
+		rank := 0
+		for _, v := range sv.Violations {
+			rank = max(rank, v.Severity.Rank())
+		}
+		return rank
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		if wi, wj := worst(top[i]), worst(top[j]); wi != wj {
+			return wi > wj
+		}
+		return len(top[i].Violations) > len(top[j].Violations)
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// syncReports writes one or more SecretComplianceReports per namespace with
+// Secrets in scope of policy and deletes reports that are no longer needed.
+// scope holds the names of the in-scope Secrets per namespace and findings is
+// ordered by namespace and name. When namespaces is not nil only reports in
+// those namespaces are touched.
+func (r *SecretPolicyReconciler) syncReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	scope map[string][]string,
+	findings []compliancev1alpha1.SecretViolationStatus,
+	namespaces map[string]bool,
+) error {
+	base := reportName(policy)
+	ref := compliancev1alpha1.ReportPolicyReference{
+		Kind:       internalpolicy.PolicyKind(policy),
+		Name:       policy.GetName(),
+		Namespace:  policy.GetNamespace(),
+		UID:        policy.GetUID(),
+		Generation: policy.GetGeneration(),
+	}
+
+	byNamespace := map[string][]compliancev1alpha1.SecretViolationStatus{}
+	for _, sv := range findings {
+		byNamespace[sv.Namespace] = append(byNamespace[sv.Namespace], sv)
+	}
+
+	wanted := map[client.ObjectKey]bool{}
+	for ns, secrets := range scope {
+		if namespaces != nil && !namespaces[ns] {
+			continue
+		}
+
+		results := byNamespace[ns]
+		summary := compliancev1alpha1.ReportSummary{Secrets: len(secrets)}
+		for _, sv := range results {
+			summary.Violations += len(sv.Violations)
+			summary.WaivedViolations += len(sv.Waived)
+			if len(sv.Violations) > 0 {
+				summary.ViolatingSecrets++
+			}
+		}
+
+		// A namespace without findings still gets a report with its summary
+		for i := 0; i == 0 || i*reportShardSize < len(results); i++ {
+			shard := results[i*reportShardSize : min((i+1)*reportShardSize, len(results))]
+
+			report := &compliancev1alpha1.SecretComplianceReport{}
+			report.Name = shardName(base, i)
+			report.Namespace = ns
+			wanted[client.ObjectKeyFromObject(report)] = true
+
+			if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, report, func() error {
+				if report.Labels == nil {
+					report.Labels = map[string]string{}
+				}
+				report.Labels[compliancev1alpha1.ReportPolicyKindLabel] = ref.Kind
+				report.Labels[compliancev1alpha1.ReportPolicyUIDLabel] = string(ref.UID)
+				report.Policy = ref
+				report.Summary = summary
+				report.Results = shard
+				return controllerutil.SetControllerReference(policy, report, r.Scheme)
+			}); err != nil {
+				return fmt.Errorf("writing report %s/%s: %w", ns, report.Name, err)
+			}
+		}
+	}
+
+	return r.deleteReports(ctx, policy, func(report *compliancev1alpha1.SecretComplianceReport) bool {
+		if namespaces != nil && !namespaces[report.Namespace] {
+			return false
+		}
+		return !wanted[client.ObjectKeyFromObject(report)]
+	})
+}
+
+// deleteReports deletes the SecretComplianceReports of policy for which
+// stale returns true.
+func (r *SecretPolicyReconciler) deleteReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	stale func(*compliancev1alpha1.SecretComplianceReport) bool,
+) error {
+	var reports compliancev1alpha1.SecretComplianceReportList
+	if err := r.List(ctx, &reports,
+		client.InNamespace(policy.GetNamespace()),
+		client.MatchingLabels{compliancev1alpha1.ReportPolicyUIDLabel: string(policy.GetUID())},
+	); err != nil {
+		return err
+	}
+
+	for i := range reports.Items {
+		if !stale(&reports.Items[i]) {
+			continue
+		}
+		if err := r.Delete(ctx, &reports.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting report %s/%s: %w", reports.Items[i].Namespace, reports.Items[i].Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("SecretComplianceReports", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		r   *ClusterSecretPolicyReconciler
+		key = types.NamespacedName{Name: "baseline"}
+	)
+
+	violating := func(namespace, name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "db"}},
+			Type:       corev1.SecretTypeBasicAuth,
+		}
+	}
+
+	reconcilePolicy := func() *compliancev1alpha1.ClusterSecretPolicy {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var policy compliancev1alpha1.ClusterSecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		return &policy
+	}
+
+	reports := func() []compliancev1alpha1.SecretComplianceReport {
+		var list compliancev1alpha1.SecretComplianceReportList
+		Expect(c.List(ctx, &list)).To(Succeed())
+		return list.Items
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&compliancev1alpha1.ClusterSecretPolicy{}).
+			WithObjects(
+				&compliancev1alpha1.ClusterSecretPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Generation: 1, UID: "policy-uid"},
+					Spec: compliancev1alpha1.SecretPolicySpec{
+						SecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+						AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+					},
+				},
+				violating("team-a", "db-1"),
+				violating("team-a", "db-2"),
+				violating("team-b", "db-1"),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "clean", Namespace: "team-c", Labels: map[string]string{"app": "db"}},
+					Type:       corev1.SecretTypeOpaque,
+				},
+			).
+			Build()
+		r = &ClusterSecretPolicyReconciler{SecretPolicyReconciler{
+			Client:   c,
+			Scheme:   scheme.Scheme,
+			Recorder: record.NewFakeRecorder(1000),
+		}}
+	})
+
+	It("writes one report per namespace and keeps only aggregates in the status", func() {
+		policy := reconcilePolicy()
+		Expect(policy.Status.ReportName).To(Equal("clustersecretpolicy-baseline"))
+		Expect(policy.Status.EnforcedSecrets).To(Equal(4))
+		Expect(policy.Status.ViolatingSecrets).To(Equal(3))
+		Expect(policy.Status.SecretViolations).To(HaveLen(3))
+
+		Expect(reports()).To(HaveLen(3))
+
+		var report compliancev1alpha1.SecretComplianceReport
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team-a", Name: policy.Status.ReportName}, &report)).To(Succeed())
+		Expect(report.Policy.Kind).To(Equal("ClusterSecretPolicy"))
+		Expect(report.Policy.Name).To(Equal("baseline"))
+		Expect(report.Labels).To(HaveKeyWithValue(compliancev1alpha1.ReportPolicyUIDLabel, "policy-uid"))
+		Expect(report.OwnerReferences).To(HaveLen(1))
+		Expect(report.Summary.Secrets).To(Equal(2))
+		Expect(report.Summary.ViolatingSecrets).To(Equal(2))
+		Expect(report.Results).To(HaveLen(2))
+		Expect(report.Results[0].Name).To(Equal("db-1"))
+
+		By("reporting namespaces without findings")
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team-c", Name: policy.Status.ReportName}, &report)).To(Succeed())
+		Expect(report.Summary.Secrets).To(Equal(1))
+		Expect(report.Results).To(BeEmpty())
+	})
+
+	It("caps the findings kept in the status", func() {
+		for i := range compliancev1alpha1.MaxStatusSecretViolations + 5 {
+			Expect(c.Create(ctx, violating("team-d", fmt.Sprintf("db-%02d", i)))).To(Succeed())
+		}
+
+		policy := reconcilePolicy()
+		Expect(policy.Status.ViolatingSecrets).To(Equal(compliancev1alpha1.MaxStatusSecretViolations + 8))
+		Expect(policy.Status.SecretViolations).To(HaveLen(compliancev1alpha1.MaxStatusSecretViolations))
+	})
+
+	It("shards namespaces with many findings", func() {
+		for i := range reportShardSize + 10 {
+			Expect(c.Create(ctx, violating("team-d", fmt.Sprintf("db-%03d", i)))).To(Succeed())
+		}
+
+		policy := reconcilePolicy()
+
+		var first, second compliancev1alpha1.SecretComplianceReport
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team-d", Name: policy.Status.ReportName}, &first)).To(Succeed())
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team-d", Name: policy.Status.ReportName + "-1"}, &second)).To(Succeed())
+		Expect(first.Results).To(HaveLen(reportShardSize))
+		Expect(second.Results).To(HaveLen(10))
+		Expect(second.Summary).To(Equal(first.Summary))
+		Expect(first.Summary.ViolatingSecrets).To(Equal(reportShardSize + 10))
+	})
+
+	It("removes the report of a namespace whose Secrets left the scope", func() {
+		policy := reconcilePolicy()
+
+		secret := violating("team-b", "db-1")
+		Expect(c.Delete(ctx, secret)).To(Succeed())
+		Expect(r.secretToPolicies("ClusterSecretPolicy")(ctx, secret)).To(HaveLen(1))
+
+		policy = reconcilePolicy()
+		Expect(policy.Status.ViolatingSecrets).To(Equal(2))
+
+		var report compliancev1alpha1.SecretComplianceReport
+		err := c.Get(ctx, types.NamespacedName{Namespace: "team-b", Name: policy.Status.ReportName}, &report)
+		Expect(err).To(HaveOccurred())
+		Expect(reports()).To(HaveLen(2))
+	})
+
+	It("deletes all reports when the policy is disabled", func() {
+		reconcilePolicy()
+		Expect(reports()).NotTo(BeEmpty())
+
+		var policy compliancev1alpha1.ClusterSecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionDisabled
+		policy.Generation++
+		Expect(c.Update(ctx, &policy)).To(Succeed())
+
+		Expect(reconcilePolicy().Status.ReportName).To(BeEmpty())
+		Expect(reports()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Report helpers", func() {
+	It("keeps report names valid for long policy names", func() {
+		policy := &compliancev1alpha1.SecretPolicy{ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("a", validation.DNS1123SubdomainMaxLength),
+			Namespace: "team-a",
+		}}
+
+		name := shardName(reportName(policy), 99)
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(reportName(policy)).To(HavePrefix("secretpolicy-aaa"))
+	})
+
+	It("orders the status findings by severity", func() {
+		finding := func(name string, severities ...compliancev1alpha1.Severity) compliancev1alpha1.SecretViolationStatus {
+			sv := compliancev1alpha1.SecretViolationStatus{Namespace: "team-a", Name: name}
+			for _, s := range severities {
+				sv.Violations = append(sv.Violations, compliancev1alpha1.Violation{Severity: s})
+			}
+			return sv
+		}
+
+		top := topSecretViolations([]compliancev1alpha1.SecretViolationStatus{
+			finding("low", compliancev1alpha1.SeverityLow),
+			finding("waived-only"),
+			finding("critical", compliancev1alpha1.SeverityCritical),
+			finding("high-twice", compliancev1alpha1.SeverityHigh, compliancev1alpha1.SeverityHigh),
+			finding("high", compliancev1alpha1.SeverityHigh),
+		}, 3)
+
+		var names []string
+		for _, sv := range top {
+			names = append(names, sv.Name)
+		}
+		Expect(names).To(Equal([]string{"critical", "high-twice", "high"}))
+	})
+})
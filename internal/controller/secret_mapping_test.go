@@ -0,0 +1,205 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("pendingScans", func() {
+	policy := types.NamespacedName{Namespace: "team-a", Name: "policy"}
+	secret := types.NamespacedName{Namespace: "team-a", Name: "db"}
+
+	It("requires a full scan until the scope of a policy is known", func() {
+		var p pendingScans
+		p.addSecret(policy, secret)
+
+		secrets, full := p.take(policy)
+		Expect(secrets).To(ConsistOf(secret))
+		Expect(full).To(BeTrue())
+
+		results := newScanResults()
+		results.set(secret, true, nil)
+		p.setResults(policy, results)
+		p.addSecret(policy, secret)
+		secrets, full = p.take(policy)
+		Expect(secrets).To(ConsistOf(secret))
+		Expect(full).To(BeFalse())
+
+		secrets, full = p.take(policy)
+		Expect(secrets).To(BeEmpty())
+		Expect(full).To(BeFalse())
+	})
+
+	It("honours explicit full scan requests once", func() {
+		var p pendingScans
+		p.setResults(policy, newScanResults())
+		p.requestFull(policy)
+
+		_, full := p.take(policy)
+		Expect(full).To(BeTrue())
+		_, full = p.take(policy)
+		Expect(full).To(BeFalse())
+	})
+
+	It("tracks the in-scope count and findings and forgets policies", func() {
+		var p pendingScans
+		entry := &compliancev1alpha1.SecretViolationStatus{Namespace: secret.Namespace, Name: secret.Name}
+		Expect(p.updateResult(policy, secret, true, entry)).To(Equal(1))
+		Expect(p.updateResult(policy, secret, true, entry)).To(Equal(1))
+
+		scope, findings := p.snapshot(policy)
+		Expect(scope).To(Equal(map[string][]string{"team-a": {"db"}}))
+		Expect(findings).To(ConsistOf(*entry))
+
+		Expect(p.updateResult(policy, secret, false, nil)).To(Equal(0))
+		scope, findings = p.snapshot(policy)
+		Expect(scope).To(BeEmpty())
+		Expect(findings).To(BeEmpty())
+
+		p.forget(policy)
+		_, full := p.take(policy)
+		Expect(full).To(BeTrue())
+	})
+})
+
+var _ = Describe("Secret to policy mapping", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx context.Context
+		r   *SecretPolicyReconciler
+		c   client.Client
+	)
+
+	newSecret := func(name string, labels map[string]string, secretType corev1.SecretType) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Type:       secretType,
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		}
+	}
+
+	newPolicy := func(name string, selector map[string]string) *compliancev1alpha1.SecretPolicy {
+		return &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				SecretSelector: &metav1.LabelSelector{MatchLabels: selector},
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+			},
+		}
+	}
+
+	reconcilePolicy := func(name string) *compliancev1alpha1.SecretPolicy {
+		key := types.NamespacedName{Namespace: namespace, Name: name}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		return &policy
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&compliancev1alpha1.SecretPolicy{}).
+			WithObjects(
+				newPolicy("db-policy", map[string]string{"app": "db"}),
+				newPolicy("web-policy", map[string]string{"app": "web"}),
+				newSecret("db-ok", map[string]string{"app": "db"}, corev1.SecretTypeOpaque),
+			).
+			Build()
+		r = &SecretPolicyReconciler{Client: c, Scheme: scheme.Scheme, Recorder: record.NewFakeRecorder(100)}
+	})
+
+	It("maps a Secret only to the policies that select it", func() {
+		mapFn := r.secretToPolicies("SecretPolicy")
+
+		requests := mapFn(ctx, newSecret("db-ok", map[string]string{"app": "db"}, corev1.SecretTypeOpaque))
+		Expect(requests).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: namespace, Name: "db-policy"},
+		}))
+
+		Expect(mapFn(ctx, newSecret("other", map[string]string{"app": "cache"}, corev1.SecretTypeOpaque))).To(BeEmpty())
+		Expect(r.secretToPolicies("ClusterSecretPolicy")(ctx, newSecret("db-ok", map[string]string{"app": "db"}, corev1.SecretTypeOpaque))).To(BeEmpty())
+	})
+
+	It("re-evaluates only the changed Secret after the first full scan", func() {
+		policy := reconcilePolicy("db-policy")
+		Expect(policy.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(policy.Status.EnforcedSecrets).To(Equal(1))
+		Expect(policy.Status.SecretViolations).To(BeEmpty())
+
+		By("adding a violating Secret")
+		bad := newSecret("db-bad", map[string]string{"app": "db"}, corev1.SecretTypeBasicAuth)
+		Expect(c.Create(ctx, bad)).To(Succeed())
+		Expect(r.secretToPolicies("SecretPolicy")(ctx, bad)).To(HaveLen(1))
+
+		policy = reconcilePolicy("db-policy")
+		Expect(policy.Status.EnforcedSecrets).To(Equal(2))
+		Expect(policy.Status.SecretViolations).To(HaveLen(1))
+		Expect(policy.Status.SecretViolations[0].Name).To(Equal("db-bad"))
+		Expect(policy.Status.Violations).To(BeNumerically(">", 0))
+
+		By("deleting the violating Secret")
+		Expect(c.Delete(ctx, bad)).To(Succeed())
+		Expect(r.secretToPolicies("SecretPolicy")(ctx, bad)).To(HaveLen(1))
+
+		policy = reconcilePolicy("db-policy")
+		Expect(policy.Status.EnforcedSecrets).To(Equal(1))
+		Expect(policy.Status.SecretViolations).To(BeEmpty())
+		Expect(policy.Status.Violations).To(BeZero())
+	})
+
+	It("drops a Secret whose labels moved it out of scope", func() {
+		bad := newSecret("db-bad", map[string]string{"app": "db"}, corev1.SecretTypeBasicAuth)
+		Expect(c.Create(ctx, bad)).To(Succeed())
+
+		policy := reconcilePolicy("db-policy")
+		Expect(policy.Status.SecretViolations).To(HaveLen(1))
+
+		// An update maps both the old and the new object
+		moved := bad.DeepCopy()
+		moved.Labels = map[string]string{"app": "web"}
+		Expect(c.Update(ctx, moved)).To(Succeed())
+		mapFn := r.secretToPolicies("SecretPolicy")
+		Expect(append(mapFn(ctx, bad), mapFn(ctx, moved)...)).To(HaveLen(2))
+
+		policy = reconcilePolicy("db-policy")
+		Expect(policy.Status.EnforcedSecrets).To(Equal(1))
+		Expect(policy.Status.SecretViolations).To(BeEmpty())
+
+		web := reconcilePolicy("web-policy")
+		Expect(web.Status.EnforcedSecrets).To(Equal(1))
+		Expect(web.Status.SecretViolations).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
+)
+
+// Defaults for ScanOptions fields left at zero.
+const (
+	DefaultScanPageSize          = 500
+	DefaultScanConcurrency       = 4
+	DefaultScanPagesPerReconcile = 10
+)
+
+// scanYieldDelay is how long a full scan that yielded waits before it
+// resumes, giving other policies a chance to reconcile.
+const scanYieldDelay = time.Second
+
+// accessRescanInterval bounds how stale the findings of access analysis get.
+// Pods, RBAC objects and other referrers of Secrets are not watched, so
+// policies that restrict ServiceAccounts or detect unused Secrets are fully
+// rescanned at this interval.
+const accessRescanInterval = 15 * time.Minute
+
+// ScanOptions bounds the memory and API load of full policy scans.
+type ScanOptions struct {
+	// PageSize is the number of Secrets requested per list call.
+	PageSize int64
+	// Concurrency is the number of Secrets evaluated in parallel.
+	Concurrency int
+	// PagesPerReconcile is the number of pages a reconcile processes before
+	// it yields and resumes the scan from the continue token on requeue.
+	// Negative values scan every page in a single reconcile.
+	PagesPerReconcile int
+}
+
+// withDefaults fills in zero fields.
+func (o ScanOptions) withDefaults() ScanOptions {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultScanPageSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultScanConcurrency
+	}
+	if o.PagesPerReconcile == 0 {
+		o.PagesPerReconcile = DefaultScanPagesPerReconcile
+	}
+	return o
+}
+
+// secretReader returns the reader used for Secret contents. Secrets are read
+// from the API server instead of the cache so that the manager never holds
+// the data of every Secret in memory.
+func (r *SecretPolicyReconciler) secretReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// scanPages continues a full scan of the Secrets selected by policy. Secrets
+// are listed in full from the API server page by page, so at most one page
+// of Secret data is held at a time, and the Secrets in scope are evaluated
+// at most Concurrency at a time. Pages are not listed as metadata: the type
+// checked by allowedTypes is not part of it, so every Secret in scope would
+// need a Get of its own. It returns true once the last page was
+// processed, and false when the scan yielded after PagesPerReconcile pages
+// with progress holding the continue token.
+func (r *SecretPolicyReconciler) scanPages(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	progress *scanProgress,
+	listOpts []client.ListOption,
+	nsLabels map[string]map[string]string,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	lookups scanLookups,
+	now time.Time,
+) (bool, error) {
+	opts := r.Scan.withDefaults()
+
+	for pages := 0; opts.PagesPerReconcile < 0 || pages < opts.PagesPerReconcile; pages++ {
+		var page corev1.SecretList
+		pageOpts := append([]client.ListOption{client.Limit(opts.PageSize), client.Continue(progress.continueToken)}, listOpts...)
+		listCtx, span := tracing.Start(ctx, "ListSecrets")
+		err := r.secretReader().List(listCtx, &page, pageOpts...)
+		span.SetAttributes(tracing.KeySecrets.Int(len(page.Items)))
+		tracing.End(span, err)
+		if err != nil {
+			return false, err
+		}
+
+		if err := r.evaluatePage(ctx, policy, progress, page.Items, nsLabels, exceptions, lookups, now, opts.Concurrency); err != nil {
+			return false, err
+		}
+
+		progress.continueToken = page.Continue
+		if page.Continue == "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// evaluatePage adds the in-scope Secrets of one page to progress.
+func (r *SecretPolicyReconciler) evaluatePage(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	progress *scanProgress,
+	items []corev1.Secret,
+	nsLabels map[string]map[string]string,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	lookups scanLookups,
+	now time.Time,
+	concurrency int,
+) error {
+	var secrets []*corev1.Secret
+	for i := range items {
+		s := &items[i]
+		inScope, err := internalpolicy.SecretInScope(s, nsLabels[s.Namespace], policy)
+		if err != nil {
+			return &invalidSelectorError{err: err}
+		}
+		if inScope {
+			secrets = append(secrets, s)
+		}
+	}
+
+	entries := make([]*compliancev1alpha1.SecretViolationStatus, len(secrets))
+
+	ctx, span := tracing.Start(ctx, "EvaluateSecrets", tracing.KeySecrets.Int(len(secrets)))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, s := range secrets {
+		g.Go(func() error {
+			entry, err := r.evaluateSecret(gctx, policy, s, exceptions, lookups, now)
+			if err != nil {
+				return err
+			}
+			entries[i] = entry
+			return nil
+		})
+	}
+	err := g.Wait()
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+
+	for i, s := range secrets {
+		progress.set(client.ObjectKeyFromObject(s), true, entries[i])
+	}
+	return nil
+}
+
+// isScanRestartable reports whether a list error means the scan has to
+// start over, e.g. because its continue token expired.
+func isScanRestartable(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// invalidSelectorError marks scan errors caused by an unparsable policy
+// selector, which only a spec change can fix.
+type invalidSelectorError struct {
+	err error
+}
+
+func (e *invalidSelectorError) Error() string { return e.err.Error() }
+
+func (e *invalidSelectorError) Unwrap() error { return e.err }
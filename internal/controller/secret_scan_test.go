@@ -0,0 +1,431 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// pagingReader adds limit/continue support to the fake client, which always
+// returns complete lists, and records which Secrets were read in full.
+type pagingReader struct {
+	client.Reader
+	mu      sync.Mutex
+	fetched []string
+	expire  bool
+}
+
+func (p *pagingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		p.mu.Lock()
+		p.fetched = append(p.fetched, key.Name)
+		p.mu.Unlock()
+	}
+	return p.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (p *pagingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var lo client.ListOptions
+	lo.ApplyOptions(opts)
+	if lo.Continue != "" && p.expire {
+		p.expire = false
+		return apierrors.NewResourceExpired("continue token expired")
+	}
+
+	if err := p.Reader.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if lo.Limit == 0 {
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	start := 0
+	if lo.Continue != "" {
+		if start, err = strconv.Atoi(lo.Continue); err != nil {
+			return err
+		}
+	}
+	end := min(start+int(lo.Limit), len(items))
+	if end < len(items) {
+		list.(metav1.ListInterface).SetContinue(strconv.Itoa(end))
+	} else {
+		list.(metav1.ListInterface).SetContinue("")
+	}
+	return meta.SetList(list, append([]runtime.Object(nil), items[start:end]...))
+}
+
+var _ = Describe("Paginated full scans", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx    context.Context
+		c      client.Client
+		reader *pagingReader
+		r      *SecretPolicyReconciler
+		key    = types.NamespacedName{Namespace: namespace, Name: "db-policy"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		objs := []client.Object{&compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace, Generation: 1},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				SecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+			},
+		}}
+		for i := range 12 {
+			secretType := corev1.SecretTypeOpaque
+			if i%2 == 0 {
+				secretType = corev1.SecretTypeBasicAuth
+			}
+			objs = append(objs, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("db-%02d", i), Namespace: namespace, Labels: map[string]string{"app": "db"}},
+				Type:       secretType,
+			})
+		}
+		for i := range 3 {
+			objs = append(objs, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: namespace, Labels: map[string]string{"app": "web"}},
+				Type:       corev1.SecretTypeBasicAuth,
+			})
+		}
+
+		c = fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&compliancev1alpha1.SecretPolicy{}).
+			WithObjects(objs...).
+			Build()
+		reader = &pagingReader{Reader: c}
+		r = &SecretPolicyReconciler{
+			Client:    c,
+			APIReader: reader,
+			Scheme:    scheme.Scheme,
+			Recorder:  record.NewFakeRecorder(100),
+			Scan:      ScanOptions{PageSize: 3, Concurrency: 2, PagesPerReconcile: 2},
+		}
+	})
+
+	// reconcileUntilDone reconciles until the scan stops yielding and
+	// returns the number of reconciles it took.
+	reconcileUntilDone := func() int {
+		for n := 1; n <= 10; n++ {
+			res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			if res.RequeueAfter != scanYieldDelay {
+				return n
+			}
+		}
+		Fail("scan did not complete")
+		return 0
+	}
+
+	It("yields between pages and resumes from the continue token", func() {
+		// 12 selected Secrets in pages of 3 take 4 pages, 2 per reconcile
+		Expect(reconcileUntilDone()).To(Equal(2))
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		Expect(policy.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(policy.Status.EnforcedSecrets).To(Equal(12))
+		Expect(policy.Status.SecretViolations).To(HaveLen(6))
+	})
+
+	It("evaluates Secrets from the listed pages without fetching them one by one", func() {
+		reconcileUntilDone()
+		Expect(reader.fetched).To(BeEmpty())
+	})
+
+	It("restarts the scan when the continue token expired", func() {
+		reader.expire = true
+		Expect(reconcileUntilDone()).To(Equal(3))
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		Expect(policy.Status.EnforcedSecrets).To(Equal(12))
+		Expect(policy.Status.SecretViolations).To(HaveLen(6))
+	})
+
+	It("does not publish a partial scan", func() {
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(scanYieldDelay))
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		Expect(policy.Status.LastScanTime).To(BeNil())
+		Expect(policy.Status.SecretViolations).To(BeEmpty())
+	})
+
+	It("reports consumers and rescans periodically when ServiceAccounts are restricted", func() {
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.AccessRules.AllowedServiceAccounts = []string{"db"}
+		Expect(c.Update(ctx, &policy)).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "web",
+				Containers:         []corev1.Container{{Name: "web", Image: "web"}},
+				ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "db-01"}},
+			},
+		})).To(Succeed())
+
+		r.Scan.PagesPerReconcile = -1
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(accessRescanInterval))
+
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		var found []compliancev1alpha1.Violation
+		for _, sv := range policy.Status.SecretViolations {
+			if sv.Name == "db-01" {
+				found = sv.Violations
+			}
+		}
+		Expect(found).To(ConsistOf(HaveField("RuleID", "accessRules.consumers")))
+	})
+
+	It("records, reports and labels unused Secrets", func() {
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.Unused = &compliancev1alpha1.UnusedSpec{AfterDays: 1, LabelForCleanup: true}
+		Expect(c.Update(ctx, &policy)).To(Succeed())
+
+		var stale corev1.Secret
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-01"}, &stale)).To(Succeed())
+		stale.Annotations = map[string]string{
+			internalpolicy.UnusedSinceAnnotation: time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339),
+		}
+		Expect(c.Update(ctx, &stale)).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+			Spec: corev1.PodSpec{
+				Containers:       []corev1.Container{{Name: "web", Image: "web"}},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "db-03"}},
+			},
+		})).To(Succeed())
+
+		r.Scan.PagesPerReconcile = -1
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(accessRescanInterval))
+
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		var found []compliancev1alpha1.Violation
+		for _, sv := range policy.Status.SecretViolations {
+			if sv.Name == "db-01" {
+				found = sv.Violations
+			}
+		}
+		Expect(found).To(ConsistOf(HaveField("RuleID", internalpolicy.RuleUnused)))
+
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-01"}, &stale)).To(Succeed())
+		Expect(stale.Labels).To(HaveKeyWithValue(internalpolicy.CleanupLabel, internalpolicy.CleanupLabelUnused))
+
+		var recent, used corev1.Secret
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-05"}, &recent)).To(Succeed())
+		Expect(recent.Annotations).To(HaveKey(internalpolicy.UnusedSinceAnnotation))
+		Expect(recent.Labels).NotTo(HaveKey(internalpolicy.CleanupLabel))
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-03"}, &used)).To(Succeed())
+		Expect(used.Annotations).NotTo(HaveKey(internalpolicy.UnusedSinceAnnotation))
+	})
+
+	It("scans enforce policies with rotation while the webhook denies recording violating Secrets", func() {
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionEnforce
+		policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 90}
+		Expect(c.Update(ctx, &policy)).To(Succeed())
+
+		// The Secret webhook denies updates of Secrets of a disallowed type
+		r.Client = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if s, ok := obj.(*corev1.Secret); ok && s.Type != corev1.SecretTypeOpaque {
+					return apierrors.NewForbidden(corev1.Resource("secrets"), s.Name, errors.New("admission webhook denied the request"))
+				}
+				return cl.Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		r.Scan.PagesPerReconcile = -1
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		Expect(policy.Status.SecretViolations).To(HaveLen(6))
+		for _, sv := range policy.Status.SecretViolations {
+			Expect(sv.Violations).To(ConsistOf(HaveField("RuleID", internalpolicy.RuleAllowedTypes)))
+		}
+
+		var denied, recorded corev1.Secret
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-00"}, &denied)).To(Succeed())
+		Expect(denied.Annotations).NotTo(HaveKey(internalpolicy.RotatedAtAnnotation))
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "db-01"}, &recorded)).To(Succeed())
+		Expect(recorded.Annotations).To(HaveKey(internalpolicy.RotatedAtAnnotation))
+	})
+
+	It("keeps usage tracking of deleted policies where another policy tracks it", func() {
+		unusedSince := time.Now().UTC().Format(time.RFC3339)
+		mark := func(name string, labels map[string]string) {
+			var s corev1.Secret
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &s)).To(Succeed())
+			s.Annotations = map[string]string{internalpolicy.UnusedSinceAnnotation: unusedSince}
+			for k, v := range labels {
+				s.Labels[k] = v
+			}
+			s.Labels[internalpolicy.CleanupLabel] = internalpolicy.CleanupLabelUnused
+			Expect(c.Update(ctx, &s)).To(Succeed())
+		}
+		mark("db-00", nil)
+		mark("db-01", map[string]string{"tier": "kept"})
+		mark("web-0", nil)
+
+		Expect(c.Create(ctx, &compliancev1alpha1.ClusterSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "kept"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				SecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "kept"}},
+				Unused:         &compliancev1alpha1.UnusedSpec{},
+			},
+		})).To(Succeed())
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.Unused = &compliancev1alpha1.UnusedSpec{LabelForCleanup: true}
+		Expect(r.cleanupPolicyEffects(ctx, &policy)).To(Succeed())
+
+		tracked := func(name string) bool {
+			var s corev1.Secret
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &s)).To(Succeed())
+			_, recorded := s.Annotations[internalpolicy.UnusedSinceAnnotation]
+			_, labeled := s.Labels[internalpolicy.CleanupLabel]
+			Expect(labeled).To(Equal(recorded))
+			return recorded
+		}
+		Expect(tracked("db-00")).To(BeFalse())
+		// Still tracked by the ClusterSecretPolicy
+		Expect(tracked("db-01")).To(BeTrue())
+		// Never selected by the deleted policy
+		Expect(tracked("web-0")).To(BeTrue())
+	})
+
+	It("removes rotation records of deleted policies once no policy tracks rotation", func() {
+		record := func(name string, labels map[string]string) {
+			var s corev1.Secret
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &s)).To(Succeed())
+			for k, v := range labels {
+				s.Labels[k] = v
+			}
+			internalpolicy.TrackRotation(&s, nil, time.Now())
+			s.Annotations[internalpolicy.LastRotatedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+			Expect(c.Update(ctx, &s)).To(Succeed())
+		}
+		record("db-00", nil)
+		record("db-01", map[string]string{"tier": "kept"})
+		record("web-0", nil)
+
+		Expect(c.Create(ctx, &compliancev1alpha1.ClusterSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "kept"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				SecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "kept"}},
+				Rotation:       compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 90},
+			},
+		})).To(Succeed())
+
+		var policy compliancev1alpha1.SecretPolicy
+		Expect(c.Get(ctx, key, &policy)).To(Succeed())
+		policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30}
+		Expect(r.cleanupPolicyEffects(ctx, &policy)).To(Succeed())
+
+		annotations := func(name string) map[string]string {
+			var s corev1.Secret
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &s)).To(Succeed())
+			return s.Annotations
+		}
+		Expect(annotations("db-00")).NotTo(Or(
+			HaveKey(internalpolicy.DataHashAnnotation),
+			HaveKey(internalpolicy.RotatedAtAnnotation),
+			HaveKey(internalpolicy.LastRotatedAnnotation),
+		))
+		// Still tracked by the ClusterSecretPolicy
+		Expect(annotations("db-01")).To(And(
+			HaveKey(internalpolicy.DataHashAnnotation),
+			HaveKey(internalpolicy.RotatedAtAnnotation),
+			HaveKey(internalpolicy.LastRotatedAnnotation),
+		))
+		// Never selected by the deleted policy
+		Expect(annotations("web-0")).To(HaveKey(internalpolicy.DataHashAnnotation))
+	})
+
+	It("traces the list, evaluate and status update phases", func() {
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(otel.SetTracerProvider, previous)
+
+		r.Scan.PagesPerReconcile = -1
+		Expect(reconcileUntilDone()).To(Equal(1))
+
+		byName := map[string][]sdktrace.ReadOnlySpan{}
+		for _, span := range recorder.Ended() {
+			byName[span.Name()] = append(byName[span.Name()], span)
+		}
+		Expect(byName["reconcileSecretPolicy"]).To(HaveLen(1))
+		root := byName["reconcileSecretPolicy"][0].SpanContext().SpanID()
+
+		Expect(byName["ListSecrets"]).To(HaveLen(4))
+		Expect(byName["EvaluateSecrets"]).To(HaveLen(4))
+		Expect(byName["CheckSecretAgainstPolicy"]).To(HaveLen(12))
+		for _, name := range []string{"ListSecrets", "EvaluateSecrets", "WriteReports", "UpdateStatus"} {
+			Expect(byName[name]).NotTo(BeEmpty(), name)
+			for _, span := range byName[name] {
+				Expect(span.Parent().SpanID()).To(Equal(root), name)
+			}
+		}
+	})
+})
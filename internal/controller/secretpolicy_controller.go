@@ -18,22 +18,34 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/alerting"
+	"github.com/Kisor-S/secret-policy-operator/internal/metrics"
 	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
 )
 
 // SecretPolicyReconciler reconciles a SecretPolicy object
@@ -41,6 +53,25 @@ type SecretPolicyReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// Alerts delivers violations to the sinks configured in spec.alerting.
+	// Alerting is skipped when nil.
+	Alerts *alerting.Dispatcher
+
+	// APIReader reads Secrets directly from the API server. Secrets are only
+	// watched as metadata, so reading them through the cached client would
+	// cache the data of every Secret. Falls back to Client when nil.
+	APIReader client.Reader
+
+	// Scan bounds the memory and API load of full scans.
+	Scan ScanOptions
+
+	// PolicyReports mirrors the results into wg-policy PolicyReports
+	// (wgpolicyk8s.io/v1alpha2) in addition to SecretComplianceReports.
+	PolicyReports bool
+
+	// pending records which Secrets changed per policy.
+	pending pendingScans
 }
 
 const SecretPolicyFinalizer = "finalizer.secretpolicy.compliance.security.local"
@@ -50,6 +81,17 @@ const SecretPolicyFinalizer = "finalizer.secretpolicy.compliance.security.local"
 // +kubebuilder:rbac:groups=compliance.security.local,resources=secretpolicies/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=list
+// +kubebuilder:rbac:groups=apps,resources=deployments;replicasets;statefulsets;daemonsets,verbs=list
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=list
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=list
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=list
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=list
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=list
+// +kubebuilder:rbac:groups=compliance.security.local,resources=secretpolicyexceptions,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -73,45 +115,176 @@ func (r *SecretPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Initialize Kubernetes event recorder (client-go style)
 	r.Recorder = mgr.GetEventRecorderFor("secretpolicy-controller")
 
-	// Register controller with the manager
+	// Register controller with the manager. Status updates do not change the
+	// generation, so the policy's own status writes do not trigger rescans.
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&compliancev1alpha1.SecretPolicy{}). // primary resource
+		For(&compliancev1alpha1.SecretPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToPolicies("SecretPolicy")),
+			builder.OnlyMetadata,
+		).
 		Watches(
-			&corev1.Secret{},                   // secondary resource
-			&handler.EnqueueRequestForObject{}, // enqueue Secret events
+			&compliancev1alpha1.SecretPolicyException{},
+			handler.EnqueueRequestsFromMapFunc(r.exceptionToPolicy("SecretPolicy")),
+		).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToPolicies("SecretPolicy")),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
 		).
 		Named("secretpolicy"). // controller name
 		Complete(r)            // finalize
 }
 
-// Reconcile logic that handles both SecretPolicy and Secret resources
+// exceptionToPolicy maps a SecretPolicyException to the policy it references
+// when that policy is of the given kind. Exceptions can waive violations of
+// any Secret, so the policy is fully rescanned.
+func (r *SecretPolicyReconciler) exceptionToPolicy(kind string) handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		exc, ok := obj.(*compliancev1alpha1.SecretPolicyException)
+		if !ok {
+			return nil
+		}
+
+		if policyKind(exc) != kind {
+			return nil
+		}
+
+		key := types.NamespacedName{Name: exc.Spec.PolicyRef.Name}
+		if kind == "SecretPolicy" {
+			key.Namespace = exc.Namespace
+		}
+		r.pending.requestFull(key)
+		return []reconcile.Request{{NamespacedName: key}}
+	}
+}
+
+// secretToPolicies maps a Secret to the policies of the given kind that
+// select it, and records the Secret as pending for each of them. On updates
+// the map runs for both the old and the new object, so policies a Secret
+// moved out of are notified as well. Only the Secret's metadata is used, so
+// the watch can be metadata-only.
+func (r *SecretPolicyReconciler) secretToPolicies(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Labels:    obj.GetLabels(),
+		}}
+
+		policies, err := r.listPolicies(ctx, kind, secret.Namespace)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list policies for Secret", "kind", kind, "secret", client.ObjectKeyFromObject(secret))
+			return nil
+		}
+
+		var nsLabels map[string]string
+		nsLoaded := false
+
+		var requests []reconcile.Request
+		for _, p := range policies {
+			if p.GetSpec().Action() == compliancev1alpha1.EnforcementActionDisabled {
+				continue
+			}
+
+			// Namespace labels are only needed when a policy selects on them
+			if p.GetSpec().NamespaceSelector != nil && !nsLoaded {
+				var ns corev1.Namespace
+				if err := r.Get(ctx, client.ObjectKey{Name: secret.Namespace}, &ns); err == nil {
+					nsLabels = ns.Labels
+				}
+				nsLoaded = true
+			}
+
+			// Invalid selectors are reported by the policy reconcile
+			if inScope, err := internalpolicy.SecretInScope(secret, nsLabels, p); err != nil || !inScope {
+				continue
+			}
+
+			key := client.ObjectKeyFromObject(p)
+			r.pending.addSecret(key, client.ObjectKeyFromObject(secret))
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+		return requests
+	}
+}
+
+// namespaceToPolicies fully rescans the policies of the given kind that
+// select namespaces by label when a namespace's labels change.
+func (r *SecretPolicyReconciler) namespaceToPolicies(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		policies, err := r.listPolicies(ctx, kind, obj.GetName())
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list policies for Namespace", "kind", kind, "namespace", obj.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, p := range policies {
+			if p.GetSpec().NamespaceSelector == nil {
+				continue
+			}
+			key := client.ObjectKeyFromObject(p)
+			r.pending.requestFull(key)
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+		return requests
+	}
+}
+
+// listPolicies lists the SecretPolicies in namespace, or every
+// ClusterSecretPolicy, depending on kind.
+func (r *SecretPolicyReconciler) listPolicies(ctx context.Context, kind, namespace string) ([]compliancev1alpha1.PolicyObject, error) {
+	var policies []compliancev1alpha1.PolicyObject
+	if kind == "SecretPolicy" {
+		var list compliancev1alpha1.SecretPolicyList
+		if err := r.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			policies = append(policies, &list.Items[i])
+		}
+		return policies, nil
+	}
+
+	var list compliancev1alpha1.ClusterSecretPolicyList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		policies = append(policies, &list.Items[i])
+	}
+	return policies, nil
+}
+
+// Reconcile scans the Secrets of a SecretPolicy and updates its status.
 func (r *SecretPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// First: Try to fetch SecretPolicy
 	var policy compliancev1alpha1.SecretPolicy
-	if err := r.Get(ctx, req.NamespacedName, &policy); err == nil {
-		logger.Info("Reconciling SecretPolicy", "policy", req.NamespacedName)
-		return r.reconcileSecretPolicy(ctx, &policy)
-	}
-
-	// Second: Try to fetch Secret
-	var secret corev1.Secret
-	if err := r.Get(ctx, req.NamespacedName, &secret); err == nil {
-		logger.Info("Reconciling Secret", "secret", req.NamespacedName)
-		return r.reconcileSecret(ctx, &secret)
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.pending.forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Not a SecretPolicy or Secret — ignore
-	return ctrl.Result{}, nil
+	logger.Info("Reconciling SecretPolicy", "policy", req.NamespacedName)
+	return r.reconcileSecretPolicy(ctx, &policy)
 }
 
-// 1. Reconcile SecretPolicy (policy-scoped scan of ALL secrets)
-func (r *SecretPolicyReconciler) reconcileSecretPolicy(ctx context.Context, policy *compliancev1alpha1.SecretPolicy) (ctrl.Result, error) {
+// 1. Reconcile SecretPolicy or ClusterSecretPolicy (policy-scoped scan of the secrets it governs)
+func (r *SecretPolicyReconciler) reconcileSecretPolicy(ctx context.Context, policy compliancev1alpha1.PolicyObject) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "reconcileSecretPolicy", tracing.KeyPolicy.String(internalpolicy.PolicyRef(policy)))
+	defer func() { tracing.End(span, err) }()
+
 	logger := log.FromContext(ctx)
+	spec := policy.GetSpec()
+	status := policy.GetStatus()
 
 	//  Handle deletion + finalizer
-	if !policy.DeletionTimestamp.IsZero() {
+	if !policy.GetDeletionTimestamp().IsZero() {
 		// Resource is being deleted
 		if controllerutil.ContainsFinalizer(policy, SecretPolicyFinalizer) {
 			logger.Info("Running finalizer: cleaning up policy side-effects")
@@ -121,6 +294,13 @@ func (r *SecretPolicyReconciler) reconcileSecretPolicy(ctx context.Context, poli
 				return ctrl.Result{}, err
 			}
 
+			internalpolicy.ForgetPolicy(policy.GetUID())
+			metrics.ForgetPolicy(policy)
+			r.pending.forget(client.ObjectKeyFromObject(policy))
+			if r.Alerts != nil {
+				r.Alerts.Forget(policy.GetUID())
+			}
+
 			// Remove finalizer
 			controllerutil.RemoveFinalizer(policy, SecretPolicyFinalizer)
 			if err := r.Update(ctx, policy); err != nil {
@@ -134,61 +314,333 @@ func (r *SecretPolicyReconciler) reconcileSecretPolicy(ctx context.Context, poli
 
 	//  Ensure finalizer exists (on create)
 	if !controllerutil.ContainsFinalizer(policy, SecretPolicyFinalizer) {
-		logger.Info("Adding finalizer to SecretPolicy")
+		logger.Info("Adding finalizer to policy", "policy", internalpolicy.PolicyRef(policy))
 		controllerutil.AddFinalizer(policy, SecretPolicyFinalizer)
 		if err := r.Update(ctx, policy); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Fetch all Secrets
-	var secrets corev1.SecretList
-	if err := r.List(ctx, &secrets); err != nil {
+	key := client.ObjectKeyFromObject(policy)
+
+	// Disabled policies do not evaluate anything; clear any previous findings
+	if spec.Action() == compliancev1alpha1.EnforcementActionDisabled {
+		r.pending.forget(key)
+		metrics.ForgetPolicy(policy)
+		status.ObservedGeneration = policy.GetGeneration()
+		status.EnforcedSecrets = 0
+		status.Violations = 0
+		status.ViolatingSecrets = 0
+		status.SecretViolations = nil
+		status.ReportName = ""
+		status.SetCondition(metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionTrue,
+			Reason:  "PolicyDisabled",
+			Message: "Enforcement is disabled; Secrets are not evaluated",
+		})
+
+		if err := r.deleteReports(ctx, policy, func(*compliancev1alpha1.SecretComplianceReport) bool { return true }); err != nil {
+			return ctrl.Result{}, err
+		}
+		if r.PolicyReports {
+			if err := r.deletePolicyReports(ctx, policy, func(*unstructured.Unstructured) bool { return true }); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.Status().Update(ctx, policy); err != nil {
+			logger.Error(err, "Failed to update policy status")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Exceptions can only waive Secrets in their own namespace
+	var exceptions compliancev1alpha1.SecretPolicyExceptionList
+	if err := r.List(ctx, &exceptions, client.InNamespace(policy.GetNamespace())); err != nil {
 		return ctrl.Result{}, err
 	}
+	scanStart := time.Now()
+	// Referrers, Pods and RBAC objects are listed from the API server rather
+	// than the cache, so that the manager never keeps informers for them
+	lookups := newScanLookups(r.secretReader(), policy)
 
-	totalViolations := 0
-	var violationSummary []compliancev1alpha1.SecretViolationStatus
+	// Secret events only re-evaluate the Secrets that changed, unless the
+	// policy itself changed or its in-scope set is not known yet
+	changed, full := r.pending.take(key)
+	progress := r.pending.inProgress(key)
+	if progress != nil && progress.generation != policy.GetGeneration() {
+		progress = nil
+	}
+	if progress == nil && !full && len(changed) > 0 && policy.GetGeneration() == status.ObservedGeneration {
+		logger.Info("Re-evaluating changed Secrets", "count", len(changed))
+		if err := r.rescanSecrets(ctx, policy, changed, exceptions.Items, lookups, scanStart); err != nil {
+			return ctrl.Result{}, err
+		}
+		namespaces := map[string]bool{}
+		for _, secretKey := range changed {
+			namespaces[secretKey.Namespace] = true
+		}
+		return r.finishScan(ctx, policy, exceptions.Items, namespaces, scanStart)
+	}
+
+	// Fetch the Secrets selected by the policy. A namespaced SecretPolicy only
+	// sees its own namespace; a ClusterSecretPolicy lists cluster-wide.
+	listOpts := []client.ListOption{client.InNamespace(policy.GetNamespace())}
+	if spec.SecretSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(spec.SecretSelector)
+		if err != nil {
+			return r.markInvalidSelector(ctx, policy, err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: sel})
+	}
+
+	nsLabels, err := r.namespaceLabels(ctx, policy)
+	if err != nil {
+		r.pending.requestFull(key)
+		return ctrl.Result{}, err
+	}
+
+	if progress == nil {
+		progress = &scanProgress{
+			scanResults: newScanResults(),
+			generation:  policy.GetGeneration(),
+		}
+	}
+
+	// Large scans yield between pages and resume from the continue token
+	done, err := r.scanPages(ctx, policy, progress, listOpts, nsLabels, exceptions.Items, lookups, scanStart)
+	if err != nil {
+		if isScanRestartable(err) {
+			logger.Info("Continue token expired, restarting scan")
+			r.pending.setScanProgress(key, nil)
+			return ctrl.Result{RequeueAfter: scanYieldDelay}, nil
+		}
+		r.pending.setScanProgress(key, nil)
+		var selErr *invalidSelectorError
+		if errors.As(err, &selErr) {
+			return r.markInvalidSelector(ctx, policy, selErr.err)
+		}
+		r.pending.requestFull(key)
+		return ctrl.Result{}, err
+	}
+	if !done {
+		logger.Info("Scan yielded", "enforcedSecrets", len(progress.scope))
+		r.pending.setScanProgress(key, progress)
+		for _, secretKey := range changed {
+			r.pending.addSecret(key, secretKey)
+		}
+		return ctrl.Result{RequeueAfter: scanYieldDelay}, nil
+	}
+
+	r.pending.setScanProgress(key, nil)
+	r.pending.setResults(key, progress.scanResults)
+	status.EnforcedSecrets = len(progress.scope)
+
+	// Secrets that changed while a multi-reconcile scan was running may have
+	// been listed before the change
+	if err := r.rescanSecrets(ctx, policy, changed, exceptions.Items, lookups, scanStart); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return r.finishScan(ctx, policy, exceptions.Items, nil, scanStart)
+}
+
+// rescanSecrets re-evaluates the given Secrets. On failure the policy is
+// marked for a full scan so no Secret is lost.
+func (r *SecretPolicyReconciler) rescanSecrets(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	secrets []types.NamespacedName,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	lookups scanLookups,
+	now time.Time,
+) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "EvaluateSecrets", tracing.KeySecrets.Int(len(secrets)))
+	for _, secretKey := range secrets {
+		if err := r.rescanSecret(ctx, policy, secretKey, exceptions, lookups, now); err != nil {
+			r.pending.requestFull(client.ObjectKeyFromObject(policy))
+			tracing.End(span, err)
+			return err
+		}
+	}
+	span.End()
+	return nil
+}
+
+// evaluateSecret checks one Secret the policy selects and returns its status
+// entry, or nil when it has no findings. Violations are emitted as events.
+func (r *SecretPolicyReconciler) evaluateSecret(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	s *corev1.Secret,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	lookups scanLookups,
+	now time.Time,
+) (*compliancev1alpha1.SecretViolationStatus, error) {
+	if policy.GetSpec().Rotation.Enabled {
+		r.ensureRotationRecord(ctx, s)
+		metrics.ObserveRotation(s)
+	}
+	if policy.GetSpec().TLS != nil {
+		metrics.ObserveCertificate(s)
+	}
+	unused := policy.GetSpec().Unused
+	if unused != nil && lookups.references != nil {
+		r.trackUsage(ctx, s, unused, lookups.references)
+	}
+
+	start := time.Now()
+	violations := internalpolicy.CheckSecretAgainstPolicyContext(ctx, s, policy,
+		internalpolicy.WithReader(r.Client), internalpolicy.WithAccessAnalysis(),
+		internalpolicy.WithAccessIndex(lookups.access), internalpolicy.WithReferences(lookups.references))
+	violations, waived := internalpolicy.ApplyExceptions(violations, s, policy, exceptions, now)
+	metrics.ObserveEvaluation(metrics.SourceScan, policy, violations, waived, time.Since(start))
+	if unused != nil && lookups.references != nil {
+		// A failed lookup leaves the label as it is
+		if _, stale, err := internalpolicy.Unused(ctx, s, unused, lookups.references, start); err == nil {
+			r.labelForCleanup(ctx, s, unused.LabelForCleanup && stale && !ruleWaived(waived, internalpolicy.RuleUnused))
+		}
+	}
+	if len(violations) == 0 && len(waived) == 0 {
+		return nil, nil
+	}
+
+	// Keep an empty (not nil) list so the required field is serialized
+	if violations == nil {
+		violations = []compliancev1alpha1.Violation{}
+	}
+
+	// Emit Kubernetes Events
+	r.emitViolationEvents(policy, s, violations)
+
+	return &compliancev1alpha1.SecretViolationStatus{
+		Name:       s.Name,
+		Namespace:  s.Namespace,
+		Violations: violations,
+		Waived:     waived,
+	}, nil
+}
 
-	for _, s := range secrets.Items {
-		errs := internalpolicy.CheckSecretAgainstPolicy(&s, policy)
-		if len(errs) > 0 {
-			totalViolations += len(errs)
+// rescanSecret re-evaluates a single changed Secret and replaces its
+// findings in the policy's results. Deleted Secrets and Secrets that left the
+// policy's scope are removed.
+func (r *SecretPolicyReconciler) rescanSecret(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	secretKey types.NamespacedName,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	lookups scanLookups,
+	now time.Time,
+) error {
+	var entry *compliancev1alpha1.SecretViolationStatus
+	inScope := false
 
-			// Convert errors to string list
-			var msgs []string
-			for _, e := range errs {
-				msgs = append(msgs, e.Error())
+	var s corev1.Secret
+	err := r.secretReader().Get(ctx, secretKey, &s)
+	switch {
+	case apierrors.IsNotFound(err):
+		metrics.ForgetSecret(secretKey.Namespace, secretKey.Name)
+	case err != nil:
+		return err
+	default:
+		var nsLabels map[string]string
+		if policy.GetSpec().NamespaceSelector != nil {
+			var ns corev1.Namespace
+			if err := r.Get(ctx, client.ObjectKey{Name: s.Namespace}, &ns); client.IgnoreNotFound(err) != nil {
+				return err
 			}
+			nsLabels = ns.Labels
+		}
 
-			violationSummary = append(violationSummary, compliancev1alpha1.SecretViolationStatus{
-				Name:       s.Name,
-				Namespace:  s.Namespace,
-				Violations: msgs,
-			})
+		if inScope, err = internalpolicy.SecretInScope(&s, nsLabels, policy); err != nil {
+			return err
+		}
+		if inScope {
+			if entry, err = r.evaluateSecret(ctx, policy, &s, exceptions, lookups, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	policy.GetStatus().EnforcedSecrets = r.pending.updateResult(client.ObjectKeyFromObject(policy), secretKey, inScope, entry)
+	return nil
+}
+
+// finishScan derives the totals and conditions from the policy's results,
+// writes its SecretComplianceReports and status, sends alerts and schedules
+// the next scan. Only the reports of the given namespaces are rewritten; nil
+// rewrites all of them.
+func (r *SecretPolicyReconciler) finishScan(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	namespaces map[string]bool,
+	scanStart time.Time,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	spec := policy.GetSpec()
+	status := policy.GetStatus()
+	key := client.ObjectKeyFromObject(policy)
+
+	scope, findings := r.pending.snapshot(key)
 
-			// Emit Kubernetes Events
-			r.emitViolationEvents(policy, &s, errs)
+	totalViolations := 0
+	totalWaived := 0
+	violatingSecrets := 0
+	var alerts []alerting.Alert
+	for _, sv := range findings {
+		totalViolations += len(sv.Violations)
+		totalWaived += len(sv.Waived)
+		if len(sv.Violations) > 0 {
+			violatingSecrets++
+		}
+		for _, v := range sv.Violations {
+			alerts = append(alerts, alerting.Alert{
+				Policy:    internalpolicy.PolicyRef(policy),
+				Secret:    sv.Namespace + "/" + sv.Name,
+				Violation: v,
+			})
 		}
 	}
 
+	// Per-Secret findings go to the reports; the status only keeps the worst
+	if err := r.writeReports(ctx, policy, scope, findings, namespaces); err != nil {
+		r.pending.requestFull(key)
+		return ctrl.Result{}, err
+	}
+	metrics.SetViolations(policy, findings)
+	status.ReportName = reportName(policy)
+	status.ViolatingSecrets = violatingSecrets
+	status.SecretViolations = topSecretViolations(findings, compliancev1alpha1.MaxStatusSecretViolations)
+
+	mode := metrics.ScanIncremental
+	if namespaces == nil {
+		mode = metrics.ScanFull
+	}
+	metrics.ScanDuration.WithLabelValues(internalpolicy.PolicyKind(policy), mode).Observe(time.Since(scanStart).Seconds())
+
 	// Update status fields
 	now := metav1.Now()
-	policy.Status.LastScanTime = &now
-	policy.Status.EnforcedSecrets = len(secrets.Items)
-	policy.Status.Violations = totalViolations
-	policy.Status.SecretViolations = violationSummary
+	status.LastScanTime = &now
+	status.ObservedGeneration = policy.GetGeneration()
+	status.Violations = totalViolations
+	status.WaivedViolations = totalWaived
+	nextExpiry := r.setExceptionCondition(policy, exceptions, scanStart)
 
 	// Update Conditions
 	if totalViolations > 0 {
-		policy.Status.SetCondition(metav1.Condition{
+		status.SetCondition(metav1.Condition{
 			Type:    "Ready",
 			Status:  metav1.ConditionFalse,
 			Reason:  "PolicyViolations",
-			Message: fmt.Sprintf("%d violations detected", totalViolations),
+			Message: fmt.Sprintf("%d violations detected (enforcementAction=%s)", totalViolations, spec.Action()),
 		})
 	} else {
-		policy.Status.SetCondition(metav1.Condition{
+		status.SetCondition(metav1.Condition{
 			Type:    "Ready",
 			Status:  metav1.ConditionTrue,
 			Reason:  "PolicyClean",
@@ -196,75 +648,377 @@ func (r *SecretPolicyReconciler) reconcileSecretPolicy(ctx context.Context, poli
 		})
 	}
 
-	// Persist status updates
-	if err := r.Status().Update(ctx, policy); err != nil {
-		logger.Error(err, "Failed to update policy status")
+	// Persist status updates. A conflict means the status we built on is
+	// stale, so rescan everything on the retry.
+	statusCtx, span := tracing.Start(ctx, "UpdateStatus")
+	err := r.Status().Update(statusCtx, policy)
+	tracing.End(span, err)
+	if err != nil {
+		r.pending.requestFull(key)
+		return ctrl.Result{}, err
 	}
 
-	// Requeue after rotation interval (if enabled)
-	if policy.Spec.Rotation.Enabled && policy.Spec.Rotation.IntervalDays > 0 {
-		return ctrl.Result{
-			RequeueAfter: time.Duration(policy.Spec.Rotation.IntervalDays) * 24 * time.Hour,
-		}, nil
+	// Alert delivery failures are reported but never fail the scan
+	if r.Alerts != nil {
+		sent, err := r.Alerts.Dispatch(ctx, policy, alerts)
+		if err != nil {
+			logger.Error(err, "Failed to send alerts")
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, "AlertFailed", "Failed to send alerts: %v", err)
+		} else if sent > 0 {
+			logger.Info("Sent alerts", "count", sent, "method", spec.Alerting.Method)
+		}
 	}
 
-	return ctrl.Result{}, nil
+	// Requeue after rotation interval (if enabled), when the next exception
+	// expires, or when access findings are due, whichever comes first, so
+	// waived violations resurface on time
+	var requeueAfter time.Duration
+	if spec.Rotation.Enabled && spec.Rotation.IntervalDays > 0 {
+		requeueAfter = time.Duration(spec.Rotation.IntervalDays) * 24 * time.Hour
+	}
+	if !nextExpiry.IsZero() {
+		if untilExpiry := nextExpiry.Sub(scanStart); requeueAfter == 0 || untilExpiry < requeueAfter {
+			requeueAfter = untilExpiry
+		}
+	}
+	accessAnalysis := len(spec.AccessRules.AllowedServiceAccounts) > 0 || spec.Unused != nil
+	if accessAnalysis && (requeueAfter == 0 || accessRescanInterval < requeueAfter) {
+		requeueAfter = accessRescanInterval
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// 2. Reconcile Secret (evaluate single secret against all policies)
-func (r *SecretPolicyReconciler) reconcileSecret(ctx context.Context, secret *corev1.Secret) (ctrl.Result, error) {
+// writeReports syncs the policy's SecretComplianceReports and, if enabled,
+// its PolicyReports.
+func (r *SecretPolicyReconciler) writeReports(
+	ctx context.Context,
+	policy compliancev1alpha1.PolicyObject,
+	scope map[string][]string,
+	findings []compliancev1alpha1.SecretViolationStatus,
+	namespaces map[string]bool,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "WriteReports")
+	defer func() { tracing.End(span, err) }()
 
-	// List all SecretPolicies
-	var policies compliancev1alpha1.SecretPolicyList
-	if err := r.List(ctx, &policies); err != nil {
-		return ctrl.Result{}, err
+	if err := r.syncReports(ctx, policy, scope, findings, namespaces); err != nil {
+		return err
+	}
+	if r.PolicyReports {
+		return r.syncPolicyReports(ctx, policy, scope, findings, namespaces)
+	}
+	return nil
+}
+
+// setExceptionCondition records which exceptions for the policy have expired
+// and returns the earliest expiry among those still active (zero if none).
+func (r *SecretPolicyReconciler) setExceptionCondition(
+	policy compliancev1alpha1.PolicyObject,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	now time.Time,
+) time.Time {
+	var expired []string
+	var nextExpiry time.Time
+
+	for i := range exceptions {
+		exc := &exceptions[i]
+		if !internalpolicy.ExceptionTargets(exc, policy) {
+			continue
+		}
+		if internalpolicy.ExceptionExpired(exc, now) {
+			expired = append(expired, exc.Namespace+"/"+exc.Name)
+			continue
+		}
+		if nextExpiry.IsZero() || exc.Spec.ExpiresAt.Time.Before(nextExpiry) {
+			nextExpiry = exc.Spec.ExpiresAt.Time
+		}
+	}
+
+	if len(expired) > 0 {
+		policy.GetStatus().SetCondition(metav1.Condition{
+			Type:    "ExceptionsExpired",
+			Status:  metav1.ConditionTrue,
+			Reason:  "ExceptionExpired",
+			Message: fmt.Sprintf("Expired exceptions no longer waive violations: %s", strings.Join(expired, ", ")),
+		})
+	} else {
+		policy.GetStatus().SetCondition(metav1.Condition{
+			Type:    "ExceptionsExpired",
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoExpiredExceptions",
+			Message: "No expired exceptions reference this policy",
+		})
+	}
+
+	return nextExpiry
+}
+
+// ensureRotationRecord stores the operator-owned rotation record on Secrets
+// that were created before the operator or changed while the webhook was
+// unavailable. Like trackUsage, it is best effort: while the Secret webhook
+// denies updates of a Secret that violates an enforce policy, the Secret is
+// evaluated against the record it would have, and storing it is retried on
+// the next scan.
+func (r *SecretPolicyReconciler) ensureRotationRecord(ctx context.Context, secret *corev1.Secret) {
+	base := secret.DeepCopy()
+	if !internalpolicy.EnsureRotationRecord(secret, time.Now()) {
+		return
+	}
+
+	log.FromContext(ctx).Info("Recording rotation state", "secret", client.ObjectKeyFromObject(secret))
+	if err := r.Patch(ctx, secret, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to record rotation state", "secret", client.ObjectKeyFromObject(secret))
+	}
+}
+
+// scanLookups are the indexes one scan of a policy looks Secrets up in. Each
+// is nil unless the policy has the rules that need it.
+type scanLookups struct {
+	references *internalpolicy.SecretReferences
+	access     *internalpolicy.AccessIndex
+}
+
+// newScanLookups returns the indexes a scan of the policy looks Secrets up
+// in, listing through reader.
+func newScanLookups(reader client.Reader, policy compliancev1alpha1.PolicyObject) scanLookups {
+	var lookups scanLookups
+	if policy.GetSpec().Unused != nil {
+		lookups.references = internalpolicy.NewSecretReferences(reader)
 	}
+	if len(policy.GetSpec().AccessRules.AllowedServiceAccounts) > 0 {
+		lookups.access = internalpolicy.NewAccessIndex(reader)
+	}
+	return lookups
+}
 
-	for _, p := range policies.Items {
-		// errs := internalpolicy.checkSecretAgainstPolicy(secret, &p)
-		errs := internalpolicy.CheckSecretAgainstPolicy(secret, &p)
-		if len(errs) > 0 {
-			r.emitViolationEvents(&p, secret, errs)
+// trackUsage records when the Secret was first found unreferenced, or clears
+// the record once it is referenced again. Failing to store the record only
+// delays the finding, e.g. while the Secret webhook denies updates of a
+// Secret that violates another rule, so it never fails the scan.
+func (r *SecretPolicyReconciler) trackUsage(
+	ctx context.Context,
+	secret *corev1.Secret,
+	spec *compliancev1alpha1.UnusedSpec,
+	refs *internalpolicy.SecretReferences,
+) {
+	if !internalpolicy.UsageTracked(secret, spec) {
+		return
+	}
+	// A failed lookup is reported by the unused rule itself
+	referenced, err := refs.Referenced(ctx, secret)
+	if err != nil {
+		return
+	}
+
+	base := secret.DeepCopy()
+	if !internalpolicy.TrackUsage(secret, referenced, time.Now()) {
+		return
+	}
+	if err := r.Patch(ctx, secret, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to record Secret usage", "secret", client.ObjectKeyFromObject(secret))
+	}
+}
+
+// ruleWaived reports whether an exception waived a finding of the rule.
+func ruleWaived(waived []compliancev1alpha1.WaivedViolation, rule string) bool {
+	for _, w := range waived {
+		if w.RuleID == rule {
+			return true
 		}
 	}
+	return false
+}
+
+// labelForCleanup adds or removes the cleanup label of the Secret. Like
+// trackUsage, it is best effort.
+func (r *SecretPolicyReconciler) labelForCleanup(ctx context.Context, secret *corev1.Secret, unused bool) {
+	base := secret.DeepCopy()
+	_, labeled := secret.Labels[internalpolicy.CleanupLabel]
+	switch {
+	case unused && !labeled:
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[internalpolicy.CleanupLabel] = internalpolicy.CleanupLabelUnused
+	case !unused && labeled:
+		delete(secret.Labels, internalpolicy.CleanupLabel)
+	default:
+		return
+	}
+
+	log.FromContext(ctx).Info("Updating cleanup label", "secret", client.ObjectKeyFromObject(secret), "unused", unused)
+	if err := r.Patch(ctx, secret, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to update cleanup label", "secret", client.ObjectKeyFromObject(secret))
+	}
+}
+
+// effects records which operator-owned metadata of a Secret the policies
+// selecting it maintain.
+type effects struct {
+	rotation bool
+	unused   bool
+}
+
+// trackedEffects returns the metadata that policies still maintain on the
+// Secret.
+func trackedEffects(secret *corev1.Secret, nsLabels map[string]string, policies []compliancev1alpha1.PolicyObject) effects {
+	var tracked effects
+	for _, p := range policies {
+		if inScope, err := internalpolicy.SecretInScope(secret, nsLabels, p); err != nil || !inScope {
+			continue
+		}
+		tracked.rotation = tracked.rotation || p.GetSpec().Rotation.Enabled
+		tracked.unused = tracked.unused || p.GetSpec().Unused != nil
+	}
+	return tracked
+}
+
+// remainingPolicies returns the active policies of either kind that can
+// select the Secrets of the deleted policy. Policies that are being deleted
+// or are disabled no longer maintain their Secrets.
+func (r *SecretPolicyReconciler) remainingPolicies(
+	ctx context.Context,
+	deleted compliancev1alpha1.PolicyObject,
+) ([]compliancev1alpha1.PolicyObject, error) {
+	namespaced, err := r.listPolicies(ctx, "SecretPolicy", deleted.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := r.listPolicies(ctx, "ClusterSecretPolicy", "")
+	if err != nil {
+		return nil, err
+	}
 
+	ref := internalpolicy.PolicyRef(deleted)
+	var remaining []compliancev1alpha1.PolicyObject
+	for _, p := range append(namespaced, cluster...) {
+		if internalpolicy.PolicyRef(p) == ref || !p.GetDeletionTimestamp().IsZero() ||
+			p.GetSpec().Action() == compliancev1alpha1.EnforcementActionDisabled {
+			continue
+		}
+		remaining = append(remaining, p)
+	}
+	return remaining, nil
+}
+
+// namespaceLabelsFor is namespaceLabels for several policies at once.
+func (r *SecretPolicyReconciler) namespaceLabelsFor(
+	ctx context.Context,
+	policies []compliancev1alpha1.PolicyObject,
+) (map[string]map[string]string, error) {
+	for _, p := range policies {
+		if p.GetSpec().NamespaceSelector != nil {
+			return r.namespaceLabels(ctx, p)
+		}
+	}
+	return nil, nil
+}
+
+// namespaceLabels returns the labels of every namespace keyed by name. It
+// only lists namespaces when the policy actually has a namespaceSelector.
+func (r *SecretPolicyReconciler) namespaceLabels(ctx context.Context, policy compliancev1alpha1.PolicyObject) (map[string]map[string]string, error) {
+	if policy.GetSpec().NamespaceSelector == nil {
+		return nil, nil
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces); err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]map[string]string, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		labels[ns.Name] = ns.Labels
+	}
+	return labels, nil
+}
+
+// markInvalidSelector records an unparsable selector on the policy status.
+// The policy is not requeued since only a spec change can fix it.
+func (r *SecretPolicyReconciler) markInvalidSelector(ctx context.Context, policy compliancev1alpha1.PolicyObject, selErr error) (ctrl.Result, error) {
+	policy.GetStatus().SetCondition(metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  "InvalidSelector",
+		Message: selErr.Error(),
+	})
+
+	if err := r.Status().Update(ctx, policy); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update policy status")
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *SecretPolicyReconciler) cleanupPolicyEffects(ctx context.Context, policy *compliancev1alpha1.SecretPolicy) error {
+// cleanupPolicyEffects removes the operator-owned metadata the deleted policy
+// maintained from the Secrets it selected. Rotation records and usage
+// tracking are kept on Secrets that another policy still tracks.
+func (r *SecretPolicyReconciler) cleanupPolicyEffects(ctx context.Context, policy compliancev1alpha1.PolicyObject) error {
 	logger := log.FromContext(ctx)
 
-	// List all Secrets
-	var secrets corev1.SecretList
-	if err := r.List(ctx, &secrets); err != nil {
+	others, err := r.remainingPolicies(ctx, policy)
+	if err != nil {
 		return err
 	}
+	nsLabels, err := r.namespaceLabelsFor(ctx, append(others, policy))
+	if err != nil {
+		return err
+	}
+
+	// Page through the metadata of the Secrets the policy could have touched
+	continueToken := ""
+	for {
+		var secrets metav1.PartialObjectMetadataList
+		secrets.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+		if err := r.secretReader().List(ctx, &secrets,
+			client.InNamespace(policy.GetNamespace()),
+			client.Limit(r.Scan.withDefaults().PageSize),
+			client.Continue(continueToken),
+		); err != nil {
+			return err
+		}
 
-	for _, s := range secrets.Items {
-		// Remove rotation tracking annotation if present
-		changed := false
+		for i := range secrets.Items {
+			s := &secrets.Items[i]
+			secret := &corev1.Secret{ObjectMeta: s.ObjectMeta}
+			// A policy with an invalid selector never touched any Secret
+			if inScope, err := internalpolicy.SecretInScope(secret, nsLabels[s.Namespace], policy); err != nil || !inScope {
+				continue
+			}
+			tracked := trackedEffects(secret, nsLabels[s.Namespace], others)
 
-		if s.Annotations != nil {
-			if _, exists := s.Annotations["lastRotated"]; exists {
-				delete(s.Annotations, "lastRotated")
-				changed = true
+			base := s.DeepCopy()
+			if !tracked.rotation {
+				delete(s.Annotations, internalpolicy.LastRotatedAnnotation)
+				delete(s.Annotations, internalpolicy.DataHashAnnotation)
+				delete(s.Annotations, internalpolicy.RotatedAtAnnotation)
+				metrics.ObserveRotation(secret)
+			}
+			if !tracked.unused {
+				delete(s.Annotations, internalpolicy.UnusedSinceAnnotation)
+				delete(s.Labels, internalpolicy.CleanupLabel)
+			}
+			if equality.Semantic.DeepEqual(base.ObjectMeta, s.ObjectMeta) {
+				continue
 			}
-		}
 
-		if changed {
 			logger.Info("Cleaning up secret annotation from finalizer", "secret", s.Name)
-			if err := r.Update(ctx, &s); err != nil {
+			if err := r.Patch(ctx, s, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
 				return err
 			}
 		}
+
+		if continueToken = secrets.Continue; continueToken == "" {
+			break
+		}
 	}
 
 	// Clear status fields
-	policy.Status.EnforcedSecrets = 0
-	policy.Status.Violations = 0
-	policy.Status.SecretViolations = nil
-	policy.Status.LastScanTime = nil
-	policy.Status.MarkReady()
+	status := policy.GetStatus()
+	status.EnforcedSecrets = 0
+	status.Violations = 0
+	status.SecretViolations = nil
+	status.LastScanTime = nil
+	status.MarkReady()
 
 	if err := r.Status().Update(ctx, policy); err != nil {
 		return err
@@ -275,21 +1029,27 @@ func (r *SecretPolicyReconciler) cleanupPolicyEffects(ctx context.Context, polic
 		policy,
 		corev1.EventTypeNormal,
 		"PolicyFinalizerComplete",
-		"Cleanup completed for SecretPolicy %s", policy.Name,
+		"Cleanup completed for %s", internalpolicy.PolicyRef(policy),
 	)
 
 	return nil
 }
 
-// Violation Event Emitter
-func (r *SecretPolicyReconciler) emitViolationEvents(policy *compliancev1alpha1.SecretPolicy, secret *corev1.Secret, errs []error) {
-	for _, err := range errs {
-		r.Recorder.Eventf(
+// Violation Event Emitter. Rule and severity are attached as event
+// annotations so they can be filtered on without parsing the message.
+func (r *SecretPolicyReconciler) emitViolationEvents(policy compliancev1alpha1.PolicyObject, secret *corev1.Secret, violations []compliancev1alpha1.Violation) {
+	for _, v := range violations {
+		r.Recorder.AnnotatedEventf(
 			policy,
+			map[string]string{
+				"compliance.security.local/rule":     v.RuleID,
+				"compliance.security.local/severity": string(v.Severity),
+				"compliance.security.local/field":    v.Field,
+			},
 			corev1.EventTypeWarning,
 			"SecretPolicyViolation",
 			"Secret %s/%s: %s",
-			secret.Namespace, secret.Name, err.Error(),
+			secret.Namespace, secret.Name, v.String(),
 		)
 	}
 }
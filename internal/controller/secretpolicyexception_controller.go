@@ -0,0 +1,137 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// SecretPolicyExceptionReconciler keeps the "Active" condition of a
+// SecretPolicyException up to date so expired waivers are visible.
+type SecretPolicyExceptionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compliance.security.local,resources=secretpolicyexceptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compliance.security.local,resources=secretpolicyexceptions/status,verbs=get;update;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretPolicyExceptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor("secretpolicyexception-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&compliancev1alpha1.SecretPolicyException{}).
+		Named("secretpolicyexception").
+		Complete(r)
+}
+
+// Reconcile marks the exception active or expired and requeues it for its expiry time.
+func (r *SecretPolicyExceptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var exc compliancev1alpha1.SecretPolicyException
+	if err := r.Get(ctx, req.NamespacedName, &exc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := time.Now()
+	cond := metav1.Condition{
+		Type:               "Active",
+		ObservedGeneration: exc.Generation,
+	}
+	var requeueAfter time.Duration
+
+	policyFound, err := r.policyExists(ctx, &exc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case internalpolicy.ExceptionExpired(&exc, now):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "Expired"
+		cond.Message = fmt.Sprintf("Waiver expired at %s; violations are reported again",
+			exc.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+	case !policyFound:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "PolicyNotFound"
+		cond.Message = fmt.Sprintf("Referenced %s %q does not exist", policyKind(&exc), exc.Spec.PolicyRef.Name)
+		requeueAfter = exc.Spec.ExpiresAt.Sub(now)
+	default:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Active"
+		cond.Message = fmt.Sprintf("Waiving %v until %s", exc.Spec.Rules,
+			exc.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+		requeueAfter = exc.Spec.ExpiresAt.Sub(now)
+	}
+
+	// Announce the transition to expired once, for the audit trail
+	previous := meta.FindStatusCondition(exc.Status.Conditions, "Active")
+	if cond.Reason == "Expired" && (previous == nil || previous.Reason != "Expired") {
+		r.Recorder.Eventf(&exc, corev1.EventTypeWarning, "ExceptionExpired",
+			"Exception for %s %q expired; justification was: %s",
+			policyKind(&exc), exc.Spec.PolicyRef.Name, exc.Spec.Justification)
+	}
+
+	if meta.SetStatusCondition(&exc.Status.Conditions, cond) {
+		if err := r.Status().Update(ctx, &exc); err != nil {
+			logger.Error(err, "Failed to update exception status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *SecretPolicyExceptionReconciler) policyExists(ctx context.Context, exc *compliancev1alpha1.SecretPolicyException) (bool, error) {
+	var err error
+	if policyKind(exc) == "ClusterSecretPolicy" {
+		err = r.Get(ctx, client.ObjectKey{Name: exc.Spec.PolicyRef.Name}, &compliancev1alpha1.ClusterSecretPolicy{})
+	} else {
+		err = r.Get(ctx, client.ObjectKey{Namespace: exc.Namespace, Name: exc.Spec.PolicyRef.Name}, &compliancev1alpha1.SecretPolicy{})
+	}
+
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func policyKind(exc *compliancev1alpha1.SecretPolicyException) string {
+	if exc.Spec.PolicyRef.Kind == "" {
+		return "SecretPolicy"
+	}
+	return exc.Spec.PolicyRef.Kind
+}
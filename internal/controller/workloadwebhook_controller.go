@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// WorkloadWebhookName is the name of the workload webhook in its
+// ValidatingWebhookConfiguration.
+const WorkloadWebhookName = "workload.validator.kishore.dev"
+
+// Values of WorkloadWebhookReconciler.FailurePolicy.
+const (
+	WorkloadFailurePolicyAuto   = "Auto"
+	WorkloadFailurePolicyFail   = string(admissionregistrationv1.Fail)
+	WorkloadFailurePolicyIgnore = string(admissionregistrationv1.Ignore)
+)
+
+// WorkloadFailurePolicies are the valid values of
+// WorkloadWebhookReconciler.FailurePolicy.
+var WorkloadFailurePolicies = []string{WorkloadFailurePolicyAuto, WorkloadFailurePolicyFail, WorkloadFailurePolicyIgnore}
+
+// WorkloadWebhookReconciler sets the failure policy of the workload webhook.
+// The webhook is installed failing open, so that Pods can start before the
+// operator does. With FailurePolicy Auto it fails closed while an enforce
+// policy restricts allowedServiceAccounts, so that an outage of the webhook
+// cannot silently admit the workloads it exists to block.
+type WorkloadWebhookReconciler struct {
+	client.Client
+	// ConfigurationName is the name of the ValidatingWebhookConfiguration
+	// holding the workload webhook.
+	ConfigurationName string
+	// FailurePolicy is Auto, Fail or Ignore.
+	FailurePolicy string
+}
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;watch;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *WorkloadWebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if !slices.Contains(WorkloadFailurePolicies, r.FailurePolicy) {
+		return fmt.Errorf("unknown workload webhook failure policy %q", r.FailurePolicy)
+	}
+
+	// Every policy change may change the failure policy of the one webhook
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: r.ConfigurationName}}}
+	})
+	isConfiguration := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.ConfigurationName
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&admissionregistrationv1.ValidatingWebhookConfiguration{}, builder.WithPredicates(isConfiguration)).
+		Watches(&compliancev1alpha1.SecretPolicy{}, enqueue).
+		Watches(&compliancev1alpha1.ClusterSecretPolicy{}, enqueue).
+		Named("workloadwebhook").
+		Complete(r)
+}
+
+// Reconcile sets the failure policy of the workload webhook. Reapplying the
+// configuration resets it to Ignore, which is corrected here as well.
+func (r *WorkloadWebhookReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var config admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := r.Get(ctx, client.ObjectKey{Name: r.ConfigurationName}, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	failurePolicy, err := r.desiredFailurePolicy(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	base := config.DeepCopy()
+	changed := false
+	for i := range config.Webhooks {
+		webhook := &config.Webhooks[i]
+		if webhook.Name != WorkloadWebhookName || ptr.Deref(webhook.FailurePolicy, "") == failurePolicy {
+			continue
+		}
+		webhook.FailurePolicy = ptr.To(failurePolicy)
+		changed = true
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("Setting workload webhook failure policy", "failurePolicy", failurePolicy)
+	// Webhooks are replaced as a whole by a merge patch, so guard against
+	// concurrent changes such as the injection of the CA bundle
+	return ctrl.Result{}, r.Patch(ctx, &config, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
+
+// desiredFailurePolicy resolves Auto to Fail while any enforce policy
+// restricts allowedServiceAccounts, and to Ignore otherwise.
+func (r *WorkloadWebhookReconciler) desiredFailurePolicy(ctx context.Context) (admissionregistrationv1.FailurePolicyType, error) {
+	if r.FailurePolicy != WorkloadFailurePolicyAuto {
+		return admissionregistrationv1.FailurePolicyType(r.FailurePolicy), nil
+	}
+
+	var policies compliancev1alpha1.SecretPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		return "", err
+	}
+	var clusterPolicies compliancev1alpha1.ClusterSecretPolicyList
+	if err := r.List(ctx, &clusterPolicies); err != nil {
+		return "", err
+	}
+
+	specs := make([]*compliancev1alpha1.SecretPolicySpec, 0, len(policies.Items)+len(clusterPolicies.Items))
+	for i := range policies.Items {
+		specs = append(specs, &policies.Items[i].Spec)
+	}
+	for i := range clusterPolicies.Items {
+		specs = append(specs, &clusterPolicies.Items[i].Spec)
+	}
+	for _, spec := range specs {
+		if spec.Action() == compliancev1alpha1.EnforcementActionEnforce && len(spec.AccessRules.AllowedServiceAccounts) > 0 {
+			return admissionregistrationv1.Fail, nil
+		}
+	}
+	return admissionregistrationv1.Ignore, nil
+}
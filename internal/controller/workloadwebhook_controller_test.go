@@ -0,0 +1,102 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Workload webhook failure policy", func() {
+	const configName = "secret-policy-operator-validating-webhook-configuration"
+
+	var (
+		ctx context.Context
+		c   client.Client
+		r   *WorkloadWebhookReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: configName},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					{Name: "secret.validator.kishore.dev", FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+					{Name: WorkloadWebhookName, FailurePolicy: ptr.To(admissionregistrationv1.Ignore)},
+				},
+			},
+		).Build()
+		r = &WorkloadWebhookReconciler{Client: c, ConfigurationName: configName, FailurePolicy: WorkloadFailurePolicyAuto}
+	})
+
+	failurePolicies := func() []admissionregistrationv1.FailurePolicyType {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: configName}})
+		Expect(err).NotTo(HaveOccurred())
+
+		var config admissionregistrationv1.ValidatingWebhookConfiguration
+		Expect(c.Get(ctx, client.ObjectKey{Name: configName}, &config)).To(Succeed())
+		var out []admissionregistrationv1.FailurePolicyType
+		for _, w := range config.Webhooks {
+			out = append(out, *w.FailurePolicy)
+		}
+		return out
+	}
+
+	restricting := func(action compliancev1alpha1.EnforcementAction) *compliancev1alpha1.ClusterSecretPolicy {
+		return &compliancev1alpha1.ClusterSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-access"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				EnforcementAction: action,
+				AccessRules:       compliancev1alpha1.AccessRulesSpec{AllowedServiceAccounts: []string{"db"}},
+			},
+		}
+	}
+
+	It("fails closed while an enforce policy restricts ServiceAccounts", func() {
+		Expect(failurePolicies()).To(Equal([]admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Fail, admissionregistrationv1.Ignore}))
+
+		policy := restricting(compliancev1alpha1.EnforcementActionEnforce)
+		Expect(c.Create(ctx, policy)).To(Succeed())
+		Expect(failurePolicies()).To(Equal([]admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Fail, admissionregistrationv1.Fail}))
+
+		Expect(c.Delete(ctx, policy)).To(Succeed())
+		Expect(failurePolicies()).To(Equal([]admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Fail, admissionregistrationv1.Ignore}))
+	})
+
+	It("fails open for policies that do not deny", func() {
+		Expect(c.Create(ctx, restricting(compliancev1alpha1.EnforcementActionAudit))).To(Succeed())
+		Expect(failurePolicies()).To(Equal([]admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Fail, admissionregistrationv1.Ignore}))
+	})
+
+	It("applies a fixed failure policy", func() {
+		r.FailurePolicy = WorkloadFailurePolicyFail
+		Expect(failurePolicies()).To(Equal([]admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Fail, admissionregistrationv1.Fail}))
+	})
+})
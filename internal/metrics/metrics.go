@@ -0,0 +1,220 @@
+// Package metrics defines the Prometheus collectors of the operator. They are
+// registered with the controller-runtime registry and served on the
+// manager's metrics endpoint next to the built-in controller and webhook
+// metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+const namespace = "secretpolicy"
+
+// Values of the source label.
+const (
+	SourceScan    = "scan"
+	SourceWebhook = "webhook"
+)
+
+// Values of the result label of RuleEvaluations.
+const (
+	ResultPass   = "pass"
+	ResultFail   = "fail"
+	ResultWaived = "waived"
+)
+
+// Values of the decision label of AdmissionDecisions.
+const (
+	DecisionAllowed = "allowed"
+	DecisionWarned  = "warned"
+	DecisionDenied  = "denied"
+	DecisionError   = "error"
+)
+
+// Values of the mode label of ScanDuration.
+const (
+	ScanFull        = "full"
+	ScanIncremental = "incremental"
+)
+
+var policyLabels = []string{"kind", "policy_namespace", "policy"}
+
+var (
+	// RuleEvaluations counts rule evaluations per policy, rule and result.
+	RuleEvaluations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_evaluations_total",
+		Help:      "Number of times a policy rule was evaluated against a Secret, by result (pass, fail or waived).",
+	}, append(policyLabels, "rule", "source", "result"))
+
+	// EvaluationDuration observes how long evaluating one Secret against one
+	// policy takes.
+	EvaluationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "evaluation_duration_seconds",
+		Help:      "Time taken to evaluate a Secret against a policy.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	}, []string{"source"})
+
+	// ScanDuration observes how long a policy reconcile spent scanning Secrets.
+	ScanDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scan_duration_seconds",
+		Help:      "Time taken by a policy reconcile to evaluate its Secrets, by mode (full or incremental).",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"kind", "mode"})
+
+	// AdmissionDecisions counts the decisions of the validating Secret webhook.
+	AdmissionDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "admission_decisions_total",
+		Help:      "Number of Secret admission requests by operation and decision (allowed, warned, denied or error).",
+	}, []string{"operation", "decision"})
+
+	// WorkloadAdmissionDecisions counts the decisions of the validating
+	// workload webhook.
+	WorkloadAdmissionDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workload_admission_decisions_total",
+		Help:      "Number of Pod and workload admission requests by kind, operation and decision (allowed, warned, denied or error).",
+	}, []string{"kind", "operation", "decision"})
+
+	// Violations is the number of open violations found by the last scan.
+	Violations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "violations",
+		Help:      "Number of open violations per policy, Secret namespace and severity.",
+	}, append(policyLabels, "namespace", "severity"))
+
+	// SecretRotatedTimestamp is when the data of a Secret was last rotated.
+	SecretRotatedTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "secret_rotated_timestamp_seconds",
+		Help:      "Unix time the data of a Secret selected by a rotation policy last changed. Subtract from time() for the rotation age.",
+	}, []string{"namespace", "secret"})
+
+	// CertificateExpiryTimestamp is when the certificate of a TLS Secret expires.
+	CertificateExpiryTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Unix time the leaf certificate of a TLS Secret selected by a policy with TLS checks expires.",
+	}, []string{"namespace", "secret"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		RuleEvaluations,
+		EvaluationDuration,
+		ScanDuration,
+		AdmissionDecisions,
+		WorkloadAdmissionDecisions,
+		Violations,
+		SecretRotatedTimestamp,
+		CertificateExpiryTimestamp,
+	)
+}
+
+// policyLabelValues returns the kind, policy_namespace and policy labels.
+func policyLabelValues(policy compliancev1alpha1.PolicyObject) []string {
+	return []string{internalpolicy.PolicyKind(policy), policy.GetNamespace(), policy.GetName()}
+}
+
+// ObserveEvaluation records the outcome of evaluating a Secret against a
+// policy: one RuleEvaluations sample per active rule, and the time it took.
+func ObserveEvaluation(
+	source string,
+	policy compliancev1alpha1.PolicyObject,
+	violations []compliancev1alpha1.Violation,
+	waived []compliancev1alpha1.WaivedViolation,
+	took time.Duration,
+) {
+	EvaluationDuration.WithLabelValues(source).Observe(took.Seconds())
+
+	// Access analysis only runs in scans
+	var opts []internalpolicy.Option
+	if source == SourceScan {
+		opts = append(opts, internalpolicy.WithAccessAnalysis())
+	}
+
+	results := map[string]string{}
+	for _, rule := range internalpolicy.ActiveRules(policy, opts...) {
+		results[rule] = ResultPass
+	}
+	for _, w := range waived {
+		results[w.RuleID] = ResultWaived
+	}
+	// A rule with both open and waived findings fails
+	for _, v := range violations {
+		results[v.RuleID] = ResultFail
+	}
+
+	labels := policyLabelValues(policy)
+	for rule, result := range results {
+		RuleEvaluations.WithLabelValues(append(labels, rule, source, result)...).Inc()
+	}
+}
+
+// SetViolations replaces the Violations series of policy with the counts of
+// findings.
+func SetViolations(policy compliancev1alpha1.PolicyObject, findings []compliancev1alpha1.SecretViolationStatus) {
+	forgetViolations(policy)
+
+	labels := policyLabelValues(policy)
+	for _, sv := range findings {
+		for _, v := range sv.Violations {
+			Violations.WithLabelValues(append(labels, sv.Namespace, string(v.Severity))...).Inc()
+		}
+	}
+}
+
+// ObserveRotation records when the data of secret was last rotated. Secrets
+// without a trustworthy rotation record are not reported.
+func ObserveRotation(secret *corev1.Secret) {
+	if rotatedAt, ok := internalpolicy.RotatedAt(secret); ok {
+		SecretRotatedTimestamp.WithLabelValues(secret.Namespace, secret.Name).Set(float64(rotatedAt.Unix()))
+		return
+	}
+	SecretRotatedTimestamp.DeleteLabelValues(secret.Namespace, secret.Name)
+}
+
+// ObserveCertificate records when the certificate of secret expires. Secrets
+// without a parsable certificate are not reported.
+func ObserveCertificate(secret *corev1.Secret) {
+	if expiry, ok := internalpolicy.CertificateExpiry(secret); ok {
+		CertificateExpiryTimestamp.WithLabelValues(secret.Namespace, secret.Name).Set(float64(expiry.Unix()))
+		return
+	}
+	CertificateExpiryTimestamp.DeleteLabelValues(secret.Namespace, secret.Name)
+}
+
+// ForgetSecret drops the series of a deleted Secret.
+func ForgetSecret(namespace, name string) {
+	SecretRotatedTimestamp.DeleteLabelValues(namespace, name)
+	CertificateExpiryTimestamp.DeleteLabelValues(namespace, name)
+}
+
+// ForgetPolicy drops the series of a deleted or disabled policy.
+func ForgetPolicy(policy compliancev1alpha1.PolicyObject) {
+	forgetViolations(policy)
+	RuleEvaluations.DeletePartialMatch(policyLabelMap(policy))
+}
+
+func forgetViolations(policy compliancev1alpha1.PolicyObject) {
+	Violations.DeletePartialMatch(policyLabelMap(policy))
+}
+
+func policyLabelMap(policy compliancev1alpha1.PolicyObject) prometheus.Labels {
+	values := policyLabelValues(policy)
+	labels := prometheus.Labels{}
+	for i, name := range policyLabels {
+		labels[name] = values[i]
+	}
+	return labels
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+var _ = Describe("Metrics", func() {
+	var policy *compliancev1alpha1.SecretPolicy
+
+	BeforeEach(func() {
+		RuleEvaluations.Reset()
+		Violations.Reset()
+		SecretRotatedTimestamp.Reset()
+		CertificateExpiryTimestamp.Reset()
+
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-policy", Namespace: "team-a"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys: []string{"password"},
+			},
+		}
+	})
+
+	evaluations := func(rule, result string) float64 {
+		return testutil.ToFloat64(RuleEvaluations.WithLabelValues("SecretPolicy", "team-a", "db-policy", rule, SourceScan, result))
+	}
+
+	It("registers every collector with the controller-runtime registry", func() {
+		EvaluationDuration.WithLabelValues(SourceScan).Observe(0)
+		ScanDuration.WithLabelValues("SecretPolicy", ScanFull).Observe(0)
+		AdmissionDecisions.WithLabelValues("CREATE", DecisionAllowed).Inc()
+		Violations.WithLabelValues("SecretPolicy", "team-a", "db-policy", "team-a", "high").Inc()
+		SecretRotatedTimestamp.WithLabelValues("team-a", "db").Set(0)
+		CertificateExpiryTimestamp.WithLabelValues("team-a", "tls").Set(0)
+		RuleEvaluations.WithLabelValues("SecretPolicy", "team-a", "db-policy", "rule", SourceScan, ResultPass).Inc()
+
+		families, err := ctrlmetrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, f := range families {
+			names = append(names, f.GetName())
+		}
+		Expect(names).To(ContainElements(
+			"secretpolicy_rule_evaluations_total",
+			"secretpolicy_evaluation_duration_seconds",
+			"secretpolicy_scan_duration_seconds",
+			"secretpolicy_admission_decisions_total",
+			"secretpolicy_violations",
+			"secretpolicy_secret_rotated_timestamp_seconds",
+			"secretpolicy_certificate_expiry_timestamp_seconds",
+		))
+	})
+
+	It("counts every active rule once per evaluation", func() {
+		ObserveEvaluation(SourceScan, policy, []compliancev1alpha1.Violation{
+			{RuleID: internalpolicy.RuleDisallowedKeys, Severity: compliancev1alpha1.SeverityHigh},
+			{RuleID: internalpolicy.RuleDisallowedKeys, Severity: compliancev1alpha1.SeverityHigh},
+		}, nil, time.Millisecond)
+		ObserveEvaluation(SourceScan, policy, nil, []compliancev1alpha1.WaivedViolation{{
+			Violation: compliancev1alpha1.Violation{RuleID: internalpolicy.RuleAllowedTypes},
+		}}, time.Millisecond)
+
+		Expect(evaluations(internalpolicy.RuleDisallowedKeys, ResultFail)).To(Equal(1.0))
+		Expect(evaluations(internalpolicy.RuleDisallowedKeys, ResultPass)).To(Equal(1.0))
+		Expect(evaluations(internalpolicy.RuleAllowedTypes, ResultPass)).To(Equal(1.0))
+		Expect(evaluations(internalpolicy.RuleAllowedTypes, ResultWaived)).To(Equal(1.0))
+	})
+
+	It("replaces the violation series of a policy", func() {
+		finding := func(namespace string, severities ...compliancev1alpha1.Severity) compliancev1alpha1.SecretViolationStatus {
+			sv := compliancev1alpha1.SecretViolationStatus{Namespace: namespace, Name: "db"}
+			for _, s := range severities {
+				sv.Violations = append(sv.Violations, compliancev1alpha1.Violation{Severity: s})
+			}
+			return sv
+		}
+
+		SetViolations(policy, []compliancev1alpha1.SecretViolationStatus{
+			finding("team-a", compliancev1alpha1.SeverityHigh, compliancev1alpha1.SeverityHigh),
+			finding("team-b", compliancev1alpha1.SeverityLow),
+		})
+		Expect(testutil.ToFloat64(Violations.WithLabelValues("SecretPolicy", "team-a", "db-policy", "team-a", "high"))).To(Equal(2.0))
+		Expect(testutil.CollectAndCount(Violations)).To(Equal(2))
+
+		SetViolations(policy, []compliancev1alpha1.SecretViolationStatus{finding("team-a", compliancev1alpha1.SeverityLow)})
+		Expect(testutil.CollectAndCount(Violations)).To(Equal(1))
+
+		ForgetPolicy(policy)
+		Expect(testutil.CollectAndCount(Violations)).To(BeZero())
+	})
+
+	It("reports the rotation time only for tracked Secrets", func() {
+		rotated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"},
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		}
+		internalpolicy.TrackRotation(secret, nil, rotated)
+
+		ObserveRotation(secret)
+		Expect(testutil.ToFloat64(SecretRotatedTimestamp.WithLabelValues("team-a", "db"))).To(Equal(float64(rotated.Unix())))
+
+		// Data changed without the record being updated
+		secret.Data["password"] = []byte("changed")
+		ObserveRotation(secret)
+		Expect(testutil.CollectAndCount(SecretRotatedTimestamp)).To(BeZero())
+	})
+
+	It("reports the expiry of parsable TLS certificates until the Secret is deleted", func() {
+		notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notAfter.AddDate(-1, 0, 0), NotAfter: notAfter}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "team-a"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+		}
+		ObserveCertificate(secret)
+		Expect(testutil.ToFloat64(CertificateExpiryTimestamp.WithLabelValues("team-a", "ingress"))).To(Equal(float64(notAfter.Unix())))
+
+		// A Secret without a rotation record keeps its expiry series
+		ObserveRotation(secret)
+		Expect(testutil.ToFloat64(CertificateExpiryTimestamp.WithLabelValues("team-a", "ingress"))).To(Equal(float64(notAfter.Unix())))
+
+		ForgetSecret("team-a", "ingress")
+		Expect(testutil.CollectAndCount(CertificateExpiryTimestamp)).To(BeZero())
+
+		secret.Data[corev1.TLSCertKey] = []byte("not a certificate")
+		ObserveCertificate(secret)
+		Expect(testutil.CollectAndCount(CertificateExpiryTimestamp)).To(BeZero())
+	})
+})
@@ -0,0 +1,579 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Ways a Pod consumes a Secret, in the order they are reported.
+const (
+	useVolume           = "volume"
+	useEnv              = "env"
+	useEnvFrom          = "envFrom"
+	useImagePullSecrets = "imagePullSecrets"
+)
+
+// readVerbs are the verbs that return the data of a Secret. The API server
+// only checks resourceNames for get: list and watch requests without a field
+// selector are authorized against the whole namespace, so collectionVerbs
+// grant read access to every Secret regardless of resourceNames.
+var (
+	readVerbs       = []string{"get", "list", "watch", rbacv1.VerbAll}
+	collectionVerbs = []string{"list", "watch", rbacv1.VerbAll}
+)
+
+// ServiceAccountAllowed reports whether the ServiceAccount namespace/name
+// matches an allowedServiceAccounts entry. Entries without a namespace refer
+// to the Secret's namespace.
+func ServiceAccountAllowed(namespace, name, secretNamespace string, allowed []string) bool {
+	for _, entry := range allowed {
+		ns, n, found := strings.Cut(entry, "/")
+		if !found {
+			ns, n = secretNamespace, entry
+		}
+		if ns == namespace && (n == "*" || n == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessIndex answers which workloads consume and which bindings can read
+// the Secrets of a namespace. The Pods and RBAC objects of a namespace are
+// listed on first use, cluster-wide RBAC objects once, and both are reused
+// for the lifetime of the value, so one AccessIndex serves one scan. It is
+// safe for concurrent use.
+type AccessIndex struct {
+	reader    client.Reader
+	consumers namespaceCache[map[string][]consumer]
+	grants    namespaceCache[[]readGrant]
+	cluster   namespaceCache[*clusterGrants]
+}
+
+// NewAccessIndex returns an AccessIndex that lists Pods and RBAC objects
+// through reader, page by page. Like for NewSecretReferences, reader should
+// read from the API server rather than a cache.
+func NewAccessIndex(reader client.Reader) *AccessIndex {
+	return &AccessIndex{reader: reader}
+}
+
+// consumer is a workload consuming a Secret as a ServiceAccount.
+type consumer struct {
+	serviceAccount string
+	workload       string
+	uses           map[string]bool
+}
+
+// consumersOf returns the running Pods in namespace that consume each Secret,
+// by Secret name.
+func (idx *AccessIndex) consumersOf(ctx context.Context, namespace string) (map[string][]consumer, error) {
+	return idx.consumers.get(namespace, func() (map[string][]consumer, error) {
+		consumers := map[string][]consumer{}
+		var pods corev1.PodList
+		if err := listPaged(ctx, idx.reader, &pods, func() error {
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				// Finished Pods no longer hold the Secret
+				if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+					continue
+				}
+
+				uses := map[string]map[string]bool{}
+				visitSecretRefs(&pod.Spec, func(name, use string) {
+					if uses[name] == nil {
+						uses[name] = map[string]bool{}
+					}
+					uses[name][use] = true
+				})
+				sa := serviceAccountName(&pod.Spec)
+				workload := WorkloadRef(pod)
+				for name, used := range uses {
+					consumers[name] = append(consumers[name], consumer{serviceAccount: sa, workload: workload, uses: used})
+				}
+			}
+			return nil
+		}, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		return consumers, nil
+	})
+}
+
+// checkConsumers reports the workloads in the Secret's namespace that consume
+// it while running as a ServiceAccount that is not allowed. Pods of the same
+// workload are reported once.
+func checkConsumers(ctx context.Context, idx *AccessIndex, secret *corev1.Secret, allowed []string) []compliancev1alpha1.Violation {
+	all, err := idx.consumersOf(ctx, secret.Namespace)
+	if err != nil {
+		return []compliancev1alpha1.Violation{newViolation(RuleConsumers, "", "consumers of the Secret cannot be determined: %v", err)}
+	}
+
+	consumers := map[string]*consumer{}
+	for _, c := range all[secret.Name] {
+		if ServiceAccountAllowed(secret.Namespace, c.serviceAccount, secret.Namespace, allowed) {
+			continue
+		}
+
+		key := c.serviceAccount + "\x00" + c.workload
+		if consumers[key] == nil {
+			consumers[key] = &consumer{serviceAccount: c.serviceAccount, workload: c.workload, uses: map[string]bool{}}
+		}
+		for use := range c.uses {
+			consumers[key].uses[use] = true
+		}
+	}
+
+	keys := make([]string, 0, len(consumers))
+	for key := range consumers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	found := make([]compliancev1alpha1.Violation, 0, len(keys))
+	for _, key := range keys {
+		c := consumers[key]
+		found = append(found, consumerViolation(c.workload, c.serviceAccount, c.uses))
+	}
+	return found
+}
+
+// CheckConsumer checks a workload that is about to consume the Secret, such
+// as a Pod or a Deployment at admission, against the allowedServiceAccounts
+// of the policy. It returns the violation the controller reports once the
+// workload runs.
+func CheckConsumer(
+	secret *corev1.Secret,
+	workload string,
+	spec *corev1.PodSpec,
+	policy compliancev1alpha1.PolicyObject,
+) []compliancev1alpha1.Violation {
+	allowed := policy.GetSpec().AccessRules.AllowedServiceAccounts
+	if len(allowed) == 0 {
+		return nil
+	}
+	uses := secretUses(spec, secret.Name)
+	if len(uses) == 0 {
+		return nil
+	}
+
+	sa := serviceAccountName(spec)
+	if ServiceAccountAllowed(secret.Namespace, sa, secret.Namespace, allowed) {
+		return nil
+	}
+	used := map[string]bool{}
+	for _, use := range uses {
+		used[use] = true
+	}
+	return []compliancev1alpha1.Violation{consumerViolation(workload, sa, used)}
+}
+
+// CheckReferencingWorkloads checks the Pods and workloads in the Secret's
+// namespace that already reference it against the allowedServiceAccounts of
+// the policy. The workload webhook admits a workload whose Secret does not
+// exist yet, so the Secret webhook runs this check when the Secret is
+// created. reader should read from the API server rather than a cache.
+func CheckReferencingWorkloads(
+	ctx context.Context,
+	reader client.Reader,
+	secret *corev1.Secret,
+	policy compliancev1alpha1.PolicyObject,
+) ([]compliancev1alpha1.Violation, error) {
+	if len(policy.GetSpec().AccessRules.AllowedServiceAccounts) == 0 {
+		return nil, nil
+	}
+
+	// Pods of the same workload are reported once
+	var found []compliancev1alpha1.Violation
+	seen := map[string]bool{}
+	check := func(workload string, spec *corev1.PodSpec) {
+		for _, v := range CheckConsumer(secret, workload, spec, policy) {
+			if !seen[v.Message] {
+				seen[v.Message] = true
+				found = append(found, v)
+			}
+		}
+	}
+	inNamespace := client.InNamespace(secret.Namespace)
+
+	var pods corev1.PodList
+	if err := listPaged(ctx, reader, &pods, func() error {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			check(WorkloadRef(pod), &pod.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return nil, err
+	}
+
+	if err := visitPodTemplates(ctx, reader, inNamespace, check); err != nil {
+		return nil, err
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Message < found[j].Message })
+	return found, nil
+}
+
+func consumerViolation(workload, serviceAccount string, uses map[string]bool) compliancev1alpha1.Violation {
+	var ordered []string
+	for _, use := range []string{useVolume, useEnv, useEnvFrom, useImagePullSecrets} {
+		if uses[use] {
+			ordered = append(ordered, use)
+		}
+	}
+	return newViolation(RuleConsumers, "", "%s consumes the Secret (%s) as ServiceAccount %s, which is not allowed",
+		workload, strings.Join(ordered, ", "), serviceAccount)
+}
+
+// serviceAccountName returns the ServiceAccount a Pod runs as.
+func serviceAccountName(spec *corev1.PodSpec) string {
+	if spec.ServiceAccountName == "" {
+		return "default"
+	}
+	return spec.ServiceAccountName
+}
+
+// ReferencedSecrets returns the sorted names of the Secrets a Pod spec
+// consumes.
+func ReferencedSecrets(spec *corev1.PodSpec) []string {
+	seen := map[string]bool{}
+	visitSecretRefs(spec, func(name, _ string) {
+		seen[name] = true
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// secretUses returns how a Pod spec refers to the named Secret, in the
+// order they are reported.
+func secretUses(spec *corev1.PodSpec, name string) []string {
+	seen := map[string]bool{}
+	visitSecretRefs(spec, func(ref, use string) {
+		if ref == name {
+			seen[use] = true
+		}
+	})
+
+	var uses []string
+	for _, use := range []string{useVolume, useEnv, useEnvFrom, useImagePullSecrets} {
+		if seen[use] {
+			uses = append(uses, use)
+		}
+	}
+	return uses
+}
+
+// visitSecretRefs calls visit for every reference of a Pod spec to a Secret.
+func visitSecretRefs(spec *corev1.PodSpec, visit func(name, use string)) {
+	for _, v := range spec.Volumes {
+		if v.Secret != nil {
+			visit(v.Secret.SecretName, useVolume)
+		}
+		if v.Projected != nil {
+			for _, source := range v.Projected.Sources {
+				if source.Secret != nil {
+					visit(source.Secret.Name, useVolume)
+				}
+			}
+		}
+	}
+
+	visitEnv := func(envVars []corev1.EnvVar, sources []corev1.EnvFromSource) {
+		for _, e := range envVars {
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+				visit(e.ValueFrom.SecretKeyRef.Name, useEnv)
+			}
+		}
+		for _, s := range sources {
+			if s.SecretRef != nil {
+				visit(s.SecretRef.Name, useEnvFrom)
+			}
+		}
+	}
+	for _, c := range spec.InitContainers {
+		visitEnv(c.Env, c.EnvFrom)
+	}
+	for _, c := range spec.Containers {
+		visitEnv(c.Env, c.EnvFrom)
+	}
+	for _, c := range spec.EphemeralContainers {
+		visitEnv(c.Env, c.EnvFrom)
+	}
+
+	for _, ref := range spec.ImagePullSecrets {
+		visit(ref.Name, useImagePullSecrets)
+	}
+}
+
+// WorkloadRef names the workload that owns a Pod, such as "Deployment web",
+// or the Pod itself when it has no controller. The Deployment of a
+// ReplicaSet is derived from the pod-template-hash suffix of its name.
+func WorkloadRef(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		// Pods are admitted before a generated name is assigned
+		if pod.Name == "" && pod.GenerateName != "" {
+			return "Pod " + pod.GenerateName + "*"
+		}
+		return "Pod " + pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" {
+			if deployment, ok := strings.CutSuffix(owner.Name, "-"+hash); ok {
+				return "Deployment " + deployment
+			}
+		}
+	}
+	return owner.Kind + " " + owner.Name
+}
+
+// secretAccess is the read access a role grants to the Secrets of a
+// namespace: to all of them, or to the named ones.
+type secretAccess struct {
+	all   bool
+	names map[string]bool
+}
+
+// reads reports whether the access covers the named Secret.
+func (a secretAccess) reads(name string) bool {
+	return a.all || a.names[name]
+}
+
+// readGrant is a binding whose role can read Secrets.
+type readGrant struct {
+	binding  string
+	role     string
+	subjects []rbacv1.Subject
+	access   secretAccess
+}
+
+// clusterGrants are the ClusterRoles that can read Secrets, by name, and the
+// ClusterRoleBindings that bind them.
+type clusterGrants struct {
+	roles    map[string]secretAccess
+	bindings []readGrant
+}
+
+// clusterGrantsOf returns the cluster-wide grants, listing them on first use.
+func (idx *AccessIndex) clusterGrantsOf(ctx context.Context) (*clusterGrants, error) {
+	return idx.cluster.get("", func() (*clusterGrants, error) {
+		grants := &clusterGrants{roles: map[string]secretAccess{}}
+		var roles rbacv1.ClusterRoleList
+		if err := listPaged(ctx, idx.reader, &roles, func() error {
+			for _, role := range roles.Items {
+				if access, ok := readAccess(role.Rules); ok {
+					grants.roles[role.Name] = access
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		var bindings rbacv1.ClusterRoleBindingList
+		if err := listPaged(ctx, idx.reader, &bindings, func() error {
+			for _, crb := range bindings.Items {
+				// A missing role grants nothing
+				access, ok := grants.roles[crb.RoleRef.Name]
+				if crb.RoleRef.Kind != "ClusterRole" || !ok {
+					continue
+				}
+				grants.bindings = append(grants.bindings, readGrant{
+					binding:  "ClusterRoleBinding " + crb.Name,
+					role:     crb.RoleRef.Kind + " " + crb.RoleRef.Name,
+					subjects: crb.Subjects,
+					access:   access,
+				})
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		sortGrants(grants.bindings)
+		return grants, nil
+	})
+}
+
+// grantsIn returns the RoleBindings of namespace that grant read access to
+// its Secrets, listing them on first use.
+func (idx *AccessIndex) grantsIn(ctx context.Context, namespace string) ([]readGrant, error) {
+	return idx.grants.get(namespace, func() ([]readGrant, error) {
+		cluster, err := idx.clusterGrantsOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		inNamespace := client.InNamespace(namespace)
+
+		roles := map[string]secretAccess{}
+		var roleList rbacv1.RoleList
+		if err := listPaged(ctx, idx.reader, &roleList, func() error {
+			for _, role := range roleList.Items {
+				if access, ok := readAccess(role.Rules); ok {
+					roles[role.Name] = access
+				}
+			}
+			return nil
+		}, inNamespace); err != nil {
+			return nil, err
+		}
+
+		var grants []readGrant
+		var bindings rbacv1.RoleBindingList
+		if err := listPaged(ctx, idx.reader, &bindings, func() error {
+			for _, rb := range bindings.Items {
+				// A missing role grants nothing
+				var access secretAccess
+				var ok bool
+				switch rb.RoleRef.Kind {
+				case "Role":
+					access, ok = roles[rb.RoleRef.Name]
+				case "ClusterRole":
+					access, ok = cluster.roles[rb.RoleRef.Name]
+				}
+				if !ok {
+					continue
+				}
+				grants = append(grants, readGrant{
+					binding:  "RoleBinding " + rb.Namespace + "/" + rb.Name,
+					role:     rb.RoleRef.Kind + " " + rb.RoleRef.Name,
+					subjects: rb.Subjects,
+					access:   access,
+				})
+			}
+			return nil
+		}, inNamespace); err != nil {
+			return nil, err
+		}
+		sortGrants(grants)
+		return grants, nil
+	})
+}
+
+func sortGrants(grants []readGrant) {
+	sort.Slice(grants, func(i, j int) bool { return grants[i].binding < grants[j].binding })
+}
+
+// checkRBACAccess reports the ServiceAccounts that are not allowed but can
+// read the Secret through a RoleBinding in its namespace or a
+// ClusterRoleBinding. Each subject is reported once, with the first binding
+// that grants it access.
+func checkRBACAccess(ctx context.Context, idx *AccessIndex, secret *corev1.Secret, allowed []string) []compliancev1alpha1.Violation {
+	cannotDetermine := func(err error) []compliancev1alpha1.Violation {
+		return []compliancev1alpha1.Violation{newViolation(RuleRBAC, "", "RBAC access to the Secret cannot be determined: %v", err)}
+	}
+
+	cluster, err := idx.clusterGrantsOf(ctx)
+	if err != nil {
+		return cannotDetermine(err)
+	}
+	namespaced, err := idx.grantsIn(ctx, secret.Namespace)
+	if err != nil {
+		return cannotDetermine(err)
+	}
+
+	// ClusterRoleBindings sort before RoleBindings
+	grants := map[string]string{}
+	for _, bindings := range [][]readGrant{cluster.bindings, namespaced} {
+		for _, g := range bindings {
+			if !g.access.reads(secret.Name) {
+				continue
+			}
+			for _, s := range g.subjects {
+				subject := readerSubject(s, secret.Namespace, allowed)
+				if _, seen := grants[subject]; subject == "" || seen {
+					continue
+				}
+				grants[subject] = fmt.Sprintf("%s (%s)", g.binding, g.role)
+			}
+		}
+	}
+
+	subjects := make([]string, 0, len(grants))
+	for subject := range grants {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+
+	found := make([]compliancev1alpha1.Violation, 0, len(subjects))
+	for _, subject := range subjects {
+		found = append(found, newViolation(RuleRBAC, "", "%s can read the Secret through %s but is not allowed", subject, grants[subject]))
+	}
+	return found
+}
+
+// readerSubject describes a binding subject that stands for ServiceAccounts
+// that are not allowed, or returns "". The groups of all ServiceAccounts are
+// reported unless every ServiceAccount they hold is allowed.
+func readerSubject(s rbacv1.Subject, secretNamespace string, allowed []string) string {
+	switch s.Kind {
+	case rbacv1.ServiceAccountKind:
+		ns := s.Namespace
+		// Only RoleBindings, which live in the Secret's namespace, may omit it
+		if ns == "" {
+			ns = secretNamespace
+		}
+		if ServiceAccountAllowed(ns, s.Name, secretNamespace, allowed) {
+			return ""
+		}
+		return "ServiceAccount " + ns + "/" + s.Name
+	case rbacv1.GroupKind:
+		if s.Name == "system:serviceaccounts" {
+			return "every ServiceAccount (group system:serviceaccounts)"
+		}
+		if ns, ok := strings.CutPrefix(s.Name, "system:serviceaccounts:"); ok {
+			if ServiceAccountAllowed(ns, "*", secretNamespace, allowed) {
+				return ""
+			}
+			return "every ServiceAccount of namespace " + ns + " (group " + s.Name + ")"
+		}
+	}
+	return ""
+}
+
+// readAccess returns the read access rules grant to Secrets, and whether
+// they grant any.
+func readAccess(rules []rbacv1.PolicyRule) (secretAccess, bool) {
+	var access secretAccess
+	for _, rule := range rules {
+		if !containsAny(rule.APIGroups, "", rbacv1.APIGroupAll) ||
+			!containsAny(rule.Resources, "secrets", rbacv1.ResourceAll) ||
+			!containsAny(rule.Verbs, readVerbs...) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 || containsAny(rule.Verbs, collectionVerbs...) {
+			return secretAccess{all: true}, true
+		}
+		if access.names == nil {
+			access.names = map[string]bool{}
+		}
+		for _, name := range rule.ResourceNames {
+			access.names[name] = true
+		}
+	}
+	return access, len(access.names) > 0
+}
+
+func containsAny(list []string, values ...string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Access analysis", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}, Type: corev1.SecretTypeOpaque}
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeOpaque)},
+				AccessRules: compliancev1alpha1.AccessRulesSpec{
+					AllowedServiceAccounts: []string{"api", "ops/*"},
+				},
+			},
+		}
+	})
+
+	messages := func(objs ...client.Object) []string {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+		out := []string{}
+		for _, v := range CheckSecretAgainstPolicy(secret, policy, WithAccessAnalysis(), WithAccessIndex(NewAccessIndex(reader))) {
+			out = append(out, v.RuleID+": "+v.Message)
+		}
+		return out
+	}
+
+	pod := func(name, sa string, spec corev1.PodSpec) *corev1.Pod {
+		spec.ServiceAccountName = sa
+		if len(spec.Containers) == 0 {
+			spec.Containers = []corev1.Container{{Name: "app", Image: "app"}}
+		}
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"}, Spec: spec}
+	}
+
+	Context("When finding consumers", func() {
+		It("Should report consumers running as ServiceAccounts that are not allowed", func() {
+			volume := pod("web-1", "web", corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "db",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db"}},
+			}}})
+			env := pod("batch", "", corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "app",
+				Env: []corev1.EnvVar{{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+				}}},
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}}},
+			}}})
+			pull := pod("puller", "builder", corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "db"}}})
+			allowed := pod("api", "api", corev1.PodSpec{Volumes: volume.Spec.Volumes})
+			unrelated := pod("other", "web", corev1.PodSpec{})
+
+			Expect(messages(volume, env, pull, allowed, unrelated)).To(Equal([]string{
+				"accessRules.consumers: Pod puller consumes the Secret (imagePullSecrets) as ServiceAccount builder, which is not allowed",
+				"accessRules.consumers: Pod batch consumes the Secret (env, envFrom) as ServiceAccount default, which is not allowed",
+				"accessRules.consumers: Pod web-1 consumes the Secret (volume) as ServiceAccount web, which is not allowed",
+			}))
+		})
+
+		It("Should report the Pods of one workload once and skip finished Pods", func() {
+			spec := corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name: "db",
+				VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{{
+					Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}},
+				}}}},
+			}}}
+			var pods []client.Object
+			for _, name := range []string{"web-7d9f8-a", "web-7d9f8-b"} {
+				p := pod(name, "web", spec)
+				p.Labels = map[string]string{"pod-template-hash": "7d9f8"}
+				p.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f8", UID: "rs", Controller: ptr.To(true),
+				}}
+				pods = append(pods, p)
+			}
+			done := pod("migrate-x", "web", spec)
+			done.OwnerReferences = []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: "migrate", UID: "job", Controller: ptr.To(true)}}
+			done.Status.Phase = corev1.PodSucceeded
+
+			Expect(messages(append(pods, done)...)).To(Equal([]string{
+				"accessRules.consumers: Deployment web consumes the Secret (volume) as ServiceAccount web, which is not allowed",
+			}))
+		})
+	})
+
+	Context("When analyzing RBAC", func() {
+		readSecrets := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-reader"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+		}
+
+		It("Should report ServiceAccounts and groups that can read the Secret", func() {
+			role := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-reader", Namespace: "apps"},
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}, ResourceNames: []string{"db"},
+				}},
+			}
+			roleBinding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-readers", Namespace: "apps"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "db-reader"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.ServiceAccountKind, Name: "api"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "apps"},
+					{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:ops"},
+					{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:dev"},
+					{Kind: rbacv1.UserKind, Name: "alice"},
+				},
+			}
+			clusterBinding := &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "secret-reader"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.ServiceAccountKind, Name: "backup", Namespace: "ops"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "apps"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "sync", Namespace: "tools"},
+				},
+			}
+
+			Expect(messages(role, roleBinding, readSecrets, clusterBinding)).To(Equal([]string{
+				"accessRules.rbac: ServiceAccount apps/web can read the Secret through ClusterRoleBinding readers (ClusterRole secret-reader) but is not allowed",
+				"accessRules.rbac: ServiceAccount tools/sync can read the Secret through ClusterRoleBinding readers (ClusterRole secret-reader) but is not allowed",
+				"accessRules.rbac: every ServiceAccount of namespace dev (group system:serviceaccounts:dev) can read the Secret through RoleBinding apps/db-readers (Role db-reader) but is not allowed",
+			}))
+		})
+
+		It("Should ignore roles that cannot read the Secret", func() {
+			other := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "apps"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, ResourceNames: []string{"cache"}},
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"create", "delete"}},
+					{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+				},
+			}
+			subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web"}}
+			bindings := []client.Object{
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "apps"},
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "other"},
+					Subjects:   subjects,
+				},
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "apps"},
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "missing"},
+					Subjects:   subjects,
+				},
+				// Bindings in other namespaces grant nothing on the Secret
+				&rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "readers", Namespace: "dev"},
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "secret-reader"},
+					Subjects:   subjects,
+				},
+			}
+
+			Expect(messages(append(bindings, other, readSecrets)...)).To(BeEmpty())
+		})
+
+		It("Should treat list and watch as access to every Secret regardless of resourceNames", func() {
+			named := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache-reader", Namespace: "apps"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, ResourceNames: []string{"cache"}},
+					{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"watch"}, ResourceNames: []string{"cache"}},
+				},
+			}
+			binding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache-readers", Namespace: "apps"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "cache-reader"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web"}},
+			}
+
+			Expect(messages(named, binding)).To(Equal([]string{
+				"accessRules.rbac: ServiceAccount apps/web can read the Secret through RoleBinding apps/cache-readers (Role cache-reader) but is not allowed",
+			}))
+		})
+
+		It("Should report failed lookups instead of passing", func() {
+			failing := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+				List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+					return errors.New("forbidden")
+				},
+			}).Build()
+			var out []string
+			for _, v := range CheckSecretAgainstPolicy(secret, policy, WithAccessAnalysis(), WithAccessIndex(NewAccessIndex(failing))) {
+				out = append(out, v.RuleID+": "+v.Message)
+			}
+			Expect(out).To(Equal([]string{
+				"accessRules.consumers: consumers of the Secret cannot be determined: forbidden",
+				"accessRules.rbac: RBAC access to the Secret cannot be determined: forbidden",
+			}))
+		})
+	})
+
+	It("Should list the Pods and RBAC objects of a namespace once per index", func() {
+		var mu sync.Mutex
+		lists := map[string]int{}
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			pod("web-1", "web", corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "db"}}}),
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "readers"},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "secret-reader"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "apps"}},
+			},
+			&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "secret-reader"},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+			},
+		).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return errors.New("unexpected get")
+			},
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				Expect(lo.Limit).To(BeNumerically(">", 0))
+				mu.Lock()
+				lists[fmt.Sprintf("%T %s", list, lo.Namespace)]++
+				mu.Unlock()
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+		idx := NewAccessIndex(reader)
+
+		var wg sync.WaitGroup
+		for _, name := range []string{"db", "cache", "db", "cache"} {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"}, Type: corev1.SecretTypeOpaque}
+				Expect(CheckSecretAgainstPolicy(s, policy, WithAccessAnalysis(), WithAccessIndex(idx))).To(HaveLen(map[string]int{"db": 2, "cache": 1}[name]))
+			}()
+		}
+		wg.Wait()
+		Expect(lists).To(Equal(map[string]int{
+			"*v1.PodList apps":            1,
+			"*v1.RoleList apps":           1,
+			"*v1.RoleBindingList apps":    1,
+			"*v1.ClusterRoleList ":        1,
+			"*v1.ClusterRoleBindingList ": 1,
+		}))
+	})
+
+	It("Should only analyze access when enabled", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod("web-1", "web", corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "db"}},
+		})).Build()
+		Expect(CheckSecretAgainstPolicy(secret, policy, WithAccessIndex(NewAccessIndex(reader)))).To(BeEmpty())
+		Expect(CheckSecretAgainstPolicy(secret, policy, WithAccessAnalysis())).To(BeEmpty())
+
+		Expect(ActiveRules(policy)).To(Equal([]string{RuleAllowedTypes}))
+		Expect(ActiveRules(policy, WithAccessAnalysis())).To(Equal([]string{RuleAllowedTypes, RuleConsumers, RuleRBAC}))
+	})
+})
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// RuleCELPrefix prefixes the rule ID of every CEL rule, e.g. "cel.tls-owner".
+const RuleCELPrefix = "cel."
+
+// celCostLimit bounds the work a single expression may do per Secret.
+const celCostLimit = 1000000
+
+var (
+	celEnvOnce sync.Once
+	celEnv     *cel.Env
+	celEnvErr  error
+
+	// celCache holds compiled programs per policy UID, invalidated whenever
+	// the policy generation changes.
+	celCache sync.Map // types.UID -> *compiledRules
+)
+
+type compiledRule struct {
+	rule    compliancev1alpha1.CELRule
+	program cel.Program
+	err     error
+}
+
+type compiledRules struct {
+	generation int64
+	rules      []compiledRule
+}
+
+func celEnvironment() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		celEnv, celEnvErr = cel.NewEnv(
+			cel.Variable("secret", cel.MapType(cel.StringType, cel.DynType)),
+		)
+	})
+	return celEnv, celEnvErr
+}
+
+// CompileRule type-checks a single CEL rule and returns a program for it.
+func CompileRule(rule compliancev1alpha1.CELRule) (cel.Program, error) {
+	env, err := celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(rule.Expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must return bool, got %s", ast.OutputType())
+	}
+
+	return env.Program(ast, cel.CostLimit(celCostLimit))
+}
+
+// compiledRulesFor returns the compiled CEL rules of a policy, compiling them
+// at most once per policy generation. Objects without a UID (e.g. policies
+// read from files) are compiled on every call.
+func compiledRulesFor(policy compliancev1alpha1.PolicyObject) []compiledRule {
+	uid := policy.GetUID()
+	if uid != "" {
+		if cached, ok := celCache.Load(uid); ok && cached.(*compiledRules).generation == policy.GetGeneration() {
+			return cached.(*compiledRules).rules
+		}
+	}
+
+	rules := make([]compiledRule, 0, len(policy.GetSpec().Rules))
+	for _, rule := range policy.GetSpec().Rules {
+		prg, err := CompileRule(rule)
+		rules = append(rules, compiledRule{rule: rule, program: prg, err: err})
+	}
+
+	if uid != "" {
+		celCache.Store(uid, &compiledRules{generation: policy.GetGeneration(), rules: rules})
+	}
+	return rules
+}
+
+// ForgetPolicy drops any cached state for a deleted policy.
+func ForgetPolicy(uid types.UID) {
+	celCache.Delete(uid)
+}
+
+// checkCELRules evaluates the policy's CEL rules, each in its own span. Rules
+// that fail to compile or evaluate are reported as violations so a broken
+// rule never passes silently.
+func checkCELRules(ctx context.Context, secret *corev1.Secret, policy compliancev1alpha1.PolicyObject) []compliancev1alpha1.Violation {
+	rules := compiledRulesFor(policy)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	input := map[string]any{"secret": celSecret(secret)}
+
+	var violations []compliancev1alpha1.Violation
+	for _, r := range rules {
+		violations = append(violations, checkRule(ctx, RuleCELPrefix+r.rule.Name, func() []compliancev1alpha1.Violation {
+			return evalCELRule(r, input)
+		})...)
+	}
+	return violations
+}
+
+// evalCELRule returns the violation of a single compiled rule, if any.
+func evalCELRule(r compiledRule, input map[string]any) []compliancev1alpha1.Violation {
+	v := compliancev1alpha1.Violation{
+		RuleID:   RuleCELPrefix + r.rule.Name,
+		Severity: r.rule.Severity,
+		Message:  r.rule.Message,
+	}
+	if v.Severity == "" {
+		v.Severity = compliancev1alpha1.SeverityMedium
+	}
+
+	if r.err != nil {
+		v.Message = fmt.Sprintf("rule could not be compiled: %v", r.err)
+		return []compliancev1alpha1.Violation{v}
+	}
+
+	out, _, err := r.program.Eval(input)
+	if err != nil {
+		v.Message = fmt.Sprintf("rule could not be evaluated: %v", err)
+		return []compliancev1alpha1.Violation{v}
+	}
+	if ok, isBool := out.Value().(bool); !isBool || !ok {
+		return []compliancev1alpha1.Violation{v}
+	}
+	return nil
+}
+
+// celSecret converts a Secret into the map exposed to CEL expressions.
+// Values themselves are never exposed, only their keys and sizes.
+func celSecret(secret *corev1.Secret) map[string]any {
+	keys := sortedKeys(secret.Data)
+	sizes := make(map[string]int64, len(secret.Data))
+	var total int64
+	for k, v := range secret.Data {
+		sizes[k] = int64(len(v))
+		total += int64(len(v))
+	}
+
+	labels := secret.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := secret.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	return map[string]any{
+		"metadata": map[string]any{
+			"name":        secret.Name,
+			"namespace":   secret.Namespace,
+			"labels":      labels,
+			"annotations": annotations,
+		},
+		"type":      string(secret.Type),
+		"keys":      keys,
+		"sizes":     sizes,
+		"totalSize": total,
+	}
+}
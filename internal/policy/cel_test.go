@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("CEL rules", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-tls",
+				Namespace: "apps",
+				Labels:    map[string]string{"owner": "team-a"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{UID: "cel-policy", Generation: 1},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeTLS)},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ForgetPolicy(policy.UID)
+	})
+
+	rule := func(name, expr string) compliancev1alpha1.CELRule {
+		return compliancev1alpha1.CELRule{Name: name, Expression: expr, Message: name + " failed"}
+	}
+
+	It("Should pass when every expression returns true", func() {
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{
+			rule("tls-owner", "secret.type != 'kubernetes.io/tls' || 'owner' in secret.metadata.labels"),
+			rule("size", "secret.totalSize < 1024 && secret.sizes['tls.key'] == 3"),
+			rule("keys", "secret.keys.all(k, k.startsWith('tls.'))"),
+		}
+
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should report a violation with the rule's message and severity", func() {
+		delete(secret.Labels, "owner")
+		r := rule("tls-owner", "'owner' in secret.metadata.labels")
+		r.Severity = compliancev1alpha1.SeverityHigh
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{r}
+
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"RuleID":   Equal("cel.tls-owner"),
+				"Severity": Equal(compliancev1alpha1.SeverityHigh),
+				"Message":  Equal("tls-owner failed"),
+			}),
+		))
+	})
+
+	It("Should default the severity to medium", func() {
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{rule("never", "false")}
+
+		violations := CheckSecretAgainstPolicy(secret, policy)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Severity).To(Equal(compliancev1alpha1.SeverityMedium))
+	})
+
+	It("Should fail closed when an expression cannot be evaluated", func() {
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{rule("missing", "secret.metadata.labels['absent'] == 'x'")}
+
+		violations := CheckSecretAgainstPolicy(secret, policy)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Message).To(ContainSubstring("could not be evaluated"))
+	})
+
+	It("Should recompile rules when the policy generation changes", func() {
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{rule("gen", "true")}
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{rule("gen", "false")}
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty(), "cached program is reused within a generation")
+
+		policy.Generation = 2
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(HaveLen(1))
+	})
+
+	Describe("CompileRule", func() {
+		It("Should reject expressions that do not return a bool", func() {
+			_, err := CompileRule(rule("str", "size(secret.keys) + 1"))
+			Expect(err).To(MatchError(ContainSubstring("must return bool")))
+		})
+
+		It("Should reject syntax errors", func() {
+			_, err := CompileRule(rule("broken", "secret.type ==="))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listPageSize bounds the number of objects a list call returns while
+// collecting the referrers or readers of Secrets.
+const listPageSize = 500
+
+// listPaged lists objects into list page by page and calls visit after each
+// page, so that at most one page is held at a time.
+func listPaged(ctx context.Context, reader client.Reader, list client.ObjectList, visit func() error, opts ...client.ListOption) error {
+	continueToken := ""
+	for {
+		// Start every page from an empty list, so that no fields of the
+		// previous page's items survive decoding
+		if err := meta.SetList(list, nil); err != nil {
+			return err
+		}
+		pageOpts := append([]client.ListOption{client.Limit(listPageSize), client.Continue(continueToken)}, opts...)
+		if err := reader.List(ctx, list, pageOpts...); err != nil {
+			return err
+		}
+		if err := visit(); err != nil {
+			return err
+		}
+		if continueToken = list.GetContinue(); continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// namespaceCache holds a value per namespace that is collected on first use.
+// Different namespaces are collected concurrently, while concurrent callers
+// for the same namespace wait for a single collection. Errors are kept like
+// values, so a failed namespace is not retried during the cache's lifetime.
+type namespaceCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*namespaceEntry[T]
+}
+
+type namespaceEntry[T any] struct {
+	once  sync.Once
+	value T
+	err   error
+}
+
+// get returns the value of namespace, calling collect if it is not known yet.
+func (c *namespaceCache[T]) get(namespace string, collect func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*namespaceEntry[T]{}
+	}
+	entry, ok := c.entries[namespace]
+	if !ok {
+		entry = &namespaceEntry[T]{}
+		c.entries[namespace] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = collect()
+	})
+	return entry.value, entry.err
+}
@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Defaults of the content scanning rules.
+const (
+	defaultEntropyThreshold  = 4.5
+	defaultEntropyMinLength  = 20
+	defaultPasswordMinLength = 12
+)
+
+// credentialPattern is a built-in credential format. The expressions favour
+// precision over recall: a finding should almost always be a real credential.
+type credentialPattern struct {
+	name        compliancev1alpha1.CredentialPattern
+	description string
+	re          *regexp.Regexp
+}
+
+var credentialPatterns = []credentialPattern{
+	{
+		name:        compliancev1alpha1.CredentialPatternAWSAccessKey,
+		description: "an AWS access key ID",
+		re:          regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		name:        compliancev1alpha1.CredentialPatternGitHubToken,
+		description: "a GitHub token",
+		re:          regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		name:        compliancev1alpha1.CredentialPatternPrivateKey,
+		description: "a PEM private key",
+		re:          regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY-----`),
+	},
+	{
+		name:        compliancev1alpha1.CredentialPatternJWT,
+		description: "a JSON Web Token",
+		re:          regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	{
+		name:        compliancev1alpha1.CredentialPatternSlackToken,
+		description: "a Slack token",
+		re:          regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	},
+}
+
+// commonPasswords are default and breached passwords that are rejected
+// regardless of their length, compared in lower case.
+var commonPasswords = map[string]bool{}
+
+func init() {
+	for _, p := range []string{
+		"123456", "12345678", "123456789", "1234567890", "111111", "000000",
+		"password", "password1", "password123", "passw0rd", "p@ssw0rd", "p@ssword",
+		"qwerty", "qwerty123", "abc123", "iloveyou", "letmein", "welcome", "welcome1",
+		"admin", "admin123", "administrator", "root", "toor", "guest", "test", "test123",
+		"changeme", "changeit", "default", "secret", "master", "trustno1",
+		"postgres", "mysql", "redis", "oracle", "sa", "user", "demo",
+	} {
+		commonPasswords[p] = true
+	}
+}
+
+// scanCredentials reports data values holding a known credential format.
+func scanCredentials(secret *corev1.Secret, scan *compliancev1alpha1.ContentScanSpec) []compliancev1alpha1.Violation {
+	spec := scan.Credentials
+	if isIn(secret.Type, spec.AllowedTypes) {
+		return nil
+	}
+
+	var found []compliancev1alpha1.Violation
+	for _, key := range scannedKeys(secret, scan) {
+		for _, p := range credentialPatterns {
+			if len(spec.Patterns) > 0 && !slices.Contains(spec.Patterns, p.name) {
+				continue
+			}
+			if p.name == compliancev1alpha1.CredentialPatternPrivateKey && isPrivateKeyField(secret.Type, key) {
+				continue
+			}
+			if p.re.Match(secret.Data[key]) {
+				found = append(found, newViolation(RuleCredentials, "data."+key, "key %s contains %s", key, p.description))
+			}
+		}
+	}
+	return found
+}
+
+// isPrivateKeyField reports whether the Secret type defines key as the place
+// for its private key.
+func isPrivateKeyField(t corev1.SecretType, key string) bool {
+	return (t == corev1.SecretTypeTLS && key == corev1.TLSPrivateKeyKey) ||
+		(t == corev1.SecretTypeSSHAuth && key == corev1.SSHAuthPrivateKey)
+}
+
+// scanEntropy reports data values containing a token that looks random.
+func scanEntropy(secret *corev1.Secret, scan *compliancev1alpha1.ContentScanSpec) []compliancev1alpha1.Violation {
+	spec := scan.Entropy
+	threshold := defaultEntropyThreshold
+	if spec.Threshold != nil {
+		threshold = spec.Threshold.AsApproximateFloat64()
+	}
+	minLength := spec.MinLength
+	if minLength <= 0 {
+		minLength = defaultEntropyMinLength
+	}
+
+	var found []compliancev1alpha1.Violation
+	for _, key := range scannedKeys(secret, scan) {
+		value := secret.Data[key]
+		// Binary values and PEM blocks are random by nature; private keys are
+		// left to the credential patterns
+		if !utf8.Valid(value) || bytes.Contains(value, []byte("-----BEGIN ")) {
+			continue
+		}
+		if e := maxTokenEntropy(string(value), minLength); e > threshold {
+			found = append(found, newViolation(RuleEntropy, "data."+key,
+				"key %s holds a high-entropy value (%.1f bits per character)", key, e))
+		}
+	}
+	return found
+}
+
+// maxTokenEntropy splits value into tokens, so that a token embedded in a
+// configuration file is measured on its own, and returns the highest entropy
+// of the tokens at least minLength bytes long.
+func maxTokenEntropy(value string, minLength int) float64 {
+	highest := 0.0
+	for _, token := range strings.FieldsFunc(value, isTokenSeparator) {
+		if len(token) >= minLength {
+			highest = max(highest, shannonEntropy(token))
+		}
+	}
+	return highest
+}
+
+func isTokenSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`"'=:,;&?<>()[]{}`, r)
+}
+
+// shannonEntropy returns the entropy of s in bits per byte. It is at most
+// log2(len(s)), so short strings never score high.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	entropy := 0.0
+	n := float64(len(s))
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// scanWeakPasswords reports password values that are common, equal to the
+// username of the Secret, or too short.
+func scanWeakPasswords(secret *corev1.Secret, scan *compliancev1alpha1.ContentScanSpec) []compliancev1alpha1.Violation {
+	spec := scan.WeakPasswords
+	minLength := spec.MinLength
+	if minLength <= 0 {
+		minLength = defaultPasswordMinLength
+	}
+	username := secret.Data[corev1.BasicAuthUsernameKey]
+
+	var found []compliancev1alpha1.Violation
+	for _, key := range scannedKeys(secret, scan) {
+		if !isPasswordKey(key, spec.Keys) {
+			continue
+		}
+
+		value := secret.Data[key]
+		var reason string
+		switch {
+		case isCommonPassword(string(value), spec.AdditionalPasswords):
+			reason = "it is a common or default password"
+		case len(username) > 0 && bytes.Equal(value, username):
+			reason = "it equals the username"
+		case utf8.RuneCount(value) < minLength:
+			reason = fmt.Sprintf("it is shorter than %d characters", minLength)
+		default:
+			continue
+		}
+		found = append(found, newViolation(RuleWeakPassword, "data."+key, "key %s holds a weak password: %s", key, reason))
+	}
+	return found
+}
+
+func isPasswordKey(key string, keys []string) bool {
+	if len(keys) > 0 {
+		return contains(keys, key)
+	}
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "passwd") || strings.Contains(key, "pwd")
+}
+
+func isCommonPassword(value string, additional []string) bool {
+	value = strings.TrimSpace(value)
+	if commonPasswords[strings.ToLower(value)] {
+		return true
+	}
+	for _, p := range additional {
+		if strings.EqualFold(value, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// scannedKeys returns the data keys to scan, sorted for stable findings.
+func scannedKeys(secret *corev1.Secret, scan *compliancev1alpha1.ContentScanSpec) []string {
+	return slices.DeleteFunc(sortedKeys(secret.Data), func(key string) bool {
+		return contains(scan.IgnoreKeys, key)
+	})
+}
@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// WaiveAllRules in SecretPolicyException.spec.rules waives every rule of the policy.
+const WaiveAllRules = "*"
+
+// ExceptionExpired reports whether the exception no longer applies at now.
+func ExceptionExpired(exc *compliancev1alpha1.SecretPolicyException, now time.Time) bool {
+	return !now.Before(exc.Spec.ExpiresAt.Time)
+}
+
+// ExceptionTargets reports whether the exception refers to the given policy
+// and may waive its rules. A ClusterSecretPolicy is only waived by
+// exceptions when it sets allowNamespacedExceptions.
+func ExceptionTargets(exc *compliancev1alpha1.SecretPolicyException, policy compliancev1alpha1.PolicyObject) bool {
+	if exc.Spec.PolicyRef.Name != policy.GetName() {
+		return false
+	}
+
+	if policy.GetNamespace() == "" {
+		return exc.Spec.PolicyRef.Kind == "ClusterSecretPolicy" && policy.GetSpec().AllowNamespacedExceptions
+	}
+	// SecretPolicy is the default kind and must share the exception's namespace
+	return exc.Spec.PolicyRef.Kind != "ClusterSecretPolicy" && exc.Namespace == policy.GetNamespace()
+}
+
+// ApplyExceptions splits the violations of a Secret into those still in force
+// and those waived by an active exception. Expired exceptions are ignored,
+// which makes their violations surface again.
+func ApplyExceptions(
+	violations []compliancev1alpha1.Violation,
+	secret *corev1.Secret,
+	policy compliancev1alpha1.PolicyObject,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	now time.Time,
+) (remaining []compliancev1alpha1.Violation, waived []compliancev1alpha1.WaivedViolation) {
+	for _, v := range violations {
+		if exc := waiverFor(v.RuleID, secret, policy, exceptions, now); exc != nil {
+			waived = append(waived, compliancev1alpha1.WaivedViolation{
+				Violation: v,
+				Exception: exc.Namespace + "/" + exc.Name,
+			})
+			continue
+		}
+		remaining = append(remaining, v)
+	}
+	return remaining, waived
+}
+
+func waiverFor(
+	rule string,
+	secret *corev1.Secret,
+	policy compliancev1alpha1.PolicyObject,
+	exceptions []compliancev1alpha1.SecretPolicyException,
+	now time.Time,
+) *compliancev1alpha1.SecretPolicyException {
+	if rule == "" {
+		return nil
+	}
+
+	for i := range exceptions {
+		exc := &exceptions[i]
+		if exc.Namespace != secret.Namespace || ExceptionExpired(exc, now) || !ExceptionTargets(exc, policy) {
+			continue
+		}
+		if !contains(exc.Spec.Rules, rule) && !contains(exc.Spec.Rules, WaiveAllRules) {
+			continue
+		}
+		// An invalid selector never waives anything
+		if ok, err := selectorMatches(&exc.Spec.SecretSelector, secret.Labels); err != nil || !ok {
+			continue
+		}
+		return exc
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("ApplyExceptions", func() {
+	var (
+		now       time.Time
+		secret    *corev1.Secret
+		policy    *compliancev1alpha1.SecretPolicy
+		exception compliancev1alpha1.SecretPolicyException
+		found     []compliancev1alpha1.Violation
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "apps",
+			Labels:    map[string]string{"app": "legacy"},
+		}}
+		policy = &compliancev1alpha1.SecretPolicy{ObjectMeta: metav1.ObjectMeta{Name: "strict", Namespace: "apps"}}
+		exception = compliancev1alpha1.SecretPolicyException{
+			ObjectMeta: metav1.ObjectMeta{Name: "waiver", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicyExceptionSpec{
+				PolicyRef:      compliancev1alpha1.PolicyReference{Name: "strict"},
+				Rules:          []string{RuleDisallowedKeys},
+				SecretSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}},
+				ExpiresAt:      metav1.NewTime(now.Add(time.Hour)),
+				Justification:  "migration in progress",
+			},
+		}
+		found = []compliancev1alpha1.Violation{
+			newViolation(RuleDisallowedKeys, "data.password", "key password is disallowed"),
+			newViolation(RuleRotation, "", "secret rotation interval exceeded"),
+		}
+	})
+
+	It("Should waive only the listed rules", func() {
+		remaining, waived := ApplyExceptions(found, secret, policy, []compliancev1alpha1.SecretPolicyException{exception}, now)
+		Expect(remaining).To(HaveLen(1))
+		Expect(remaining[0].RuleID).To(Equal(RuleRotation))
+		Expect(waived).To(HaveLen(1))
+		Expect(waived[0].Exception).To(Equal("apps/waiver"))
+		Expect(waived[0].RuleID).To(Equal(RuleDisallowedKeys))
+	})
+
+	It("Should waive every rule with a wildcard", func() {
+		exception.Spec.Rules = []string{WaiveAllRules}
+		remaining, waived := ApplyExceptions(found, secret, policy, []compliancev1alpha1.SecretPolicyException{exception}, now)
+		Expect(remaining).To(BeEmpty())
+		Expect(waived).To(HaveLen(2))
+	})
+
+	It("Should stop waiving once expired", func() {
+		remaining, waived := ApplyExceptions(found, secret, policy, []compliancev1alpha1.SecretPolicyException{exception}, now.Add(2*time.Hour))
+		Expect(remaining).To(HaveLen(2))
+		Expect(waived).To(BeEmpty())
+	})
+
+	It("Should not waive Secrets outside the selector", func() {
+		secret.Labels["app"] = "web"
+		remaining, _ := ApplyExceptions(found, secret, policy, []compliancev1alpha1.SecretPolicyException{exception}, now)
+		Expect(remaining).To(HaveLen(2))
+	})
+
+	It("Should match the referenced policy kind", func() {
+		cluster := &compliancev1alpha1.ClusterSecretPolicy{ObjectMeta: metav1.ObjectMeta{Name: "strict"}}
+		Expect(ExceptionTargets(&exception, cluster)).To(BeFalse())
+
+		exception.Spec.PolicyRef.Kind = "ClusterSecretPolicy"
+		cluster.Spec.AllowNamespacedExceptions = true
+		Expect(ExceptionTargets(&exception, cluster)).To(BeTrue())
+		Expect(ExceptionTargets(&exception, policy)).To(BeFalse())
+	})
+
+	It("Should only waive ClusterSecretPolicies that allow namespaced exceptions", func() {
+		cluster := &compliancev1alpha1.ClusterSecretPolicy{ObjectMeta: metav1.ObjectMeta{Name: "strict"}}
+		exception.Spec.PolicyRef.Kind = "ClusterSecretPolicy"
+		exceptions := []compliancev1alpha1.SecretPolicyException{exception}
+
+		remaining, waived := ApplyExceptions(found, secret, cluster, exceptions, now)
+		Expect(remaining).To(HaveLen(2))
+		Expect(waived).To(BeEmpty())
+
+		cluster.Spec.AllowNamespacedExceptions = true
+		remaining, waived = ApplyExceptions(found, secret, cluster, exceptions, now)
+		Expect(remaining).To(HaveLen(1))
+		Expect(waived).To(HaveLen(1))
+	})
+})
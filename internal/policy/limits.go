@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// CompileKeyPattern compiles a key name or SAN pattern of the policy.
+// Patterns match the whole name.
+func CompileKeyPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// checkAllowedKeyPatterns reports data keys that match none of the allowed
+// patterns.
+func checkAllowedKeyPatterns(secret *corev1.Secret, patterns []string) []compliancev1alpha1.Violation {
+	res, found := compileKeyPatterns(RuleAllowedKeyPatterns, "allowedKeyPatterns", patterns)
+	if len(found) > 0 {
+		return found
+	}
+
+	for _, key := range sortedKeys(secret.Data) {
+		if !matchesAny(res, key) {
+			found = append(found, newViolation(RuleAllowedKeyPatterns, "data."+key, "key %s does not match any allowed key pattern", key))
+		}
+	}
+	return found
+}
+
+// checkDisallowedKeyPatterns reports data keys that match a disallowed
+// pattern, naming the first pattern that matched.
+func checkDisallowedKeyPatterns(secret *corev1.Secret, patterns []string) []compliancev1alpha1.Violation {
+	res, found := compileKeyPatterns(RuleDisallowedKeyPatterns, "disallowedKeyPatterns", patterns)
+	if len(found) > 0 {
+		return found
+	}
+
+	for _, key := range sortedKeys(secret.Data) {
+		for i, re := range res {
+			if re.MatchString(key) {
+				found = append(found, newViolation(RuleDisallowedKeyPatterns, "data."+key, "key %s matches disallowed pattern %q", key, patterns[i]))
+				break
+			}
+		}
+	}
+	return found
+}
+
+// compileKeyPatterns compiles patterns, reporting the invalid ones as
+// violations of rule. The webhook rejects such policies, so they only reach
+// the engine through the offline CLI.
+func compileKeyPatterns(rule, fieldName string, patterns []string) ([]*regexp.Regexp, []compliancev1alpha1.Violation) {
+	var res []*regexp.Regexp
+	var found []compliancev1alpha1.Violation
+	for _, pattern := range patterns {
+		re, err := CompileKeyPattern(pattern)
+		if err != nil {
+			found = append(found, newViolation(rule, "", "invalid %s pattern %q: %v", fieldName, pattern, err))
+			continue
+		}
+		res = append(res, re)
+	}
+	return res, found
+}
+
+func matchesAny(res []*regexp.Regexp, key string) bool {
+	for _, re := range res {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMaxValueBytes reports data values larger than limit.
+func checkMaxValueBytes(secret *corev1.Secret, limit int64) []compliancev1alpha1.Violation {
+	var found []compliancev1alpha1.Violation
+	for _, key := range sortedKeys(secret.Data) {
+		if size := int64(len(secret.Data[key])); size > limit {
+			found = append(found, newViolation(RuleMaxValueBytes, "data."+key, "key %s holds %d bytes, at most %d are allowed", key, size, limit))
+		}
+	}
+	return found
+}
+
+// dataSize returns the size of the Secret's decoded data, keys included.
+func dataSize(secret *corev1.Secret) int64 {
+	var size int64
+	for key, value := range secret.Data {
+		size += int64(len(key) + len(value))
+	}
+	return size
+}
+
+// sortedKeys returns the keys of m in order, so that findings and digests
+// are stable.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
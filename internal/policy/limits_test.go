@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Key and size limits", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+			Type:       corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				"db_password": []byte("s3cr3t"),
+				"api-key":     []byte("0123456789"),
+				"server.pem":  []byte("-----BEGIN CERTIFICATE-----"),
+			},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{AllowedTypes: []string{string(corev1.SecretTypeOpaque)}},
+		}
+	})
+
+	messages := func() []string {
+		out := []string{}
+		for _, v := range CheckSecretAgainstPolicy(secret, policy) {
+			out = append(out, v.RuleID+": "+v.Message)
+		}
+		return out
+	}
+
+	It("Should report keys that match no allowed pattern", func() {
+		policy.Spec.AllowedKeyPatterns = []string{"[a-z_]+", "api-.*"}
+		Expect(messages()).To(Equal([]string{"allowedKeyPatterns: key server.pem does not match any allowed key pattern"}))
+	})
+
+	It("Should match patterns against the whole key", func() {
+		policy.Spec.DisallowedKeyPatterns = []string{"pem", ".*[.](pem|p12)"}
+		Expect(messages()).To(Equal([]string{`disallowedKeyPatterns: key server.pem matches disallowed pattern ".*[.](pem|p12)"`}))
+	})
+
+	It("Should report invalid patterns instead of passing", func() {
+		policy.Spec.DisallowedKeyPatterns = []string{"("}
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleDisallowedKeyPatterns))
+		Expect(found[0].Message).To(HavePrefix(`invalid disallowedKeyPatterns pattern "("`))
+	})
+
+	It("Should limit the number of keys and the size of values and data", func() {
+		secret.Data["firmware.bin"] = bytes.Repeat([]byte{0}, 2048)
+		policy.Spec.MaxKeys = 3
+		policy.Spec.MaxValueBytes = 1024
+		policy.Spec.MaxTotalBytes = 2048
+		Expect(messages()).To(Equal([]string{
+			"maxKeys: secret has 4 keys, at most 3 are allowed",
+			"maxValueBytes: key firmware.bin holds 2048 bytes, at most 1024 are allowed",
+			"maxTotalBytes: secret data is 2131 bytes, at most 2048 are allowed",
+		}))
+
+		delete(secret.Data, "firmware.bin")
+		Expect(messages()).To(BeEmpty())
+	})
+
+	It("Should list the configured limits as active rules", func() {
+		policy.Spec.AllowedKeyPatterns = []string{".*"}
+		policy.Spec.MaxTotalBytes = 1024
+		Expect(ActiveRules(policy)).To(Equal([]string{RuleAllowedTypes, RuleAllowedKeyPatterns, RuleMaxTotalBytes}))
+	})
+})
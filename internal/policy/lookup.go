@@ -0,0 +1,53 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// ListPolicies returns every policy that may govern a Secret in the given
+// namespace: the SecretPolicies in that namespace followed by all
+// ClusterSecretPolicies. Callers still need SecretInScope to apply selectors.
+func ListPolicies(ctx context.Context, c client.Reader, namespace string) ([]compliancev1alpha1.PolicyObject, error) {
+	var namespaced compliancev1alpha1.SecretPolicyList
+	if err := c.List(ctx, &namespaced, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var clusterWide compliancev1alpha1.ClusterSecretPolicyList
+	if err := c.List(ctx, &clusterWide); err != nil {
+		return nil, err
+	}
+
+	policies := make([]compliancev1alpha1.PolicyObject, 0, len(namespaced.Items)+len(clusterWide.Items))
+	for i := range namespaced.Items {
+		policies = append(policies, &namespaced.Items[i])
+	}
+	for i := range clusterWide.Items {
+		policies = append(policies, &clusterWide.Items[i])
+	}
+	return policies, nil
+}
+
+// PolicyRef returns a human readable reference such as "SecretPolicy apps/db"
+// or "ClusterSecretPolicy baseline" for use in messages.
+func PolicyRef(policy compliancev1alpha1.PolicyObject) string {
+	if policy.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", PolicyKind(policy), policy.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", PolicyKind(policy), policy.GetNamespace(), policy.GetName())
+}
+
+// PolicyKind returns "SecretPolicy" or "ClusterSecretPolicy". Objects read
+// through a typed client carry no TypeMeta, so the kind is derived from the
+// policy's scope.
+func PolicyKind(policy compliancev1alpha1.PolicyObject) string {
+	if policy.GetNamespace() == "" {
+		return "ClusterSecretPolicy"
+	}
+	return "SecretPolicy"
+}
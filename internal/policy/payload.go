@@ -0,0 +1,238 @@
+package policy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// dockerConfigEntry is one registry of a docker config.
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// checkPayload runs the payload rule that matches the Secret type. Secrets of
+// other types pass.
+func checkPayload(
+	ctx context.Context,
+	secret *corev1.Secret,
+	spec *compliancev1alpha1.PayloadSpec,
+	opts *options,
+) []compliancev1alpha1.Violation {
+	switch secret.Type {
+	case corev1.SecretTypeDockercfg, corev1.SecretTypeDockerConfigJson:
+		return checkDockerConfig(secret, spec)
+	case corev1.SecretTypeBasicAuth:
+		return checkBasicAuth(secret)
+	case corev1.SecretTypeSSHAuth:
+		return checkSSHAuth(secret, spec)
+	case corev1.SecretTypeServiceAccountToken:
+		return checkServiceAccountToken(ctx, secret, opts.reader)
+	}
+	return nil
+}
+
+func checkDockerConfig(secret *corev1.Secret, spec *compliancev1alpha1.PayloadSpec) []compliancev1alpha1.Violation {
+	key := corev1.DockerConfigJsonKey
+	if secret.Type == corev1.SecretTypeDockercfg {
+		key = corev1.DockerConfigKey
+	}
+	field := "data." + key
+
+	auths, err := parseDockerConfig(secret.Type, secret.Data[key])
+	if err != nil {
+		return []compliancev1alpha1.Violation{newViolation(RuleDockerConfig, field, "%s is invalid: %v", key, err)}
+	}
+
+	var found []compliancev1alpha1.Violation
+	for _, registry := range sortedRegistries(auths) {
+		if err := validateDockerAuth(auths[registry]); err != nil {
+			found = append(found, newViolation(RuleDockerConfig, field, "credentials for registry %s are invalid: %v", registry, err))
+		}
+		if len(spec.AllowedRegistries) > 0 && !registryAllowed(registryHost(registry), spec.AllowedRegistries) {
+			found = append(found, newViolation(RuleRegistry, field, "registry %s is not allowed", registry))
+		}
+	}
+	return found
+}
+
+// parseDockerConfig returns the registries of a .dockerconfigjson, which
+// nests them under "auths", or of a legacy .dockercfg, which does not.
+func parseDockerConfig(t corev1.SecretType, data []byte) (map[string]dockerConfigEntry, error) {
+	if len(data) == 0 {
+		return nil, errors.New("key is missing or empty")
+	}
+
+	var auths map[string]dockerConfigEntry
+	if t == corev1.SecretTypeDockercfg {
+		if err := json.Unmarshal(data, &auths); err != nil {
+			return nil, err
+		}
+	} else {
+		var config struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		auths = config.Auths
+	}
+	if len(auths) == 0 {
+		return nil, errors.New("no registry credentials found")
+	}
+	return auths, nil
+}
+
+func validateDockerAuth(entry dockerConfigEntry) error {
+	if entry.Auth == "" {
+		if entry.Username == "" || entry.Password == "" {
+			return errors.New("auth or username and password are required")
+		}
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return errors.New("auth is not valid base64")
+	}
+	if !strings.Contains(string(decoded), ":") {
+		return errors.New("auth must encode username:password")
+	}
+	return nil
+}
+
+func sortedRegistries(auths map[string]dockerConfigEntry) []string {
+	registries := make([]string, 0, len(auths))
+	for r := range auths {
+		registries = append(registries, r)
+	}
+	sort.Strings(registries)
+	return registries
+}
+
+// registryHost normalises a docker config key, which may be a bare host or
+// a URL such as https://index.docker.io/v1/, to host[:port].
+func registryHost(registry string) string {
+	if strings.Contains(registry, "://") {
+		if u, err := url.Parse(registry); err == nil {
+			return strings.ToLower(u.Host)
+		}
+	}
+	host, _, _ := strings.Cut(registry, "/")
+	return strings.ToLower(host)
+}
+
+func registryAllowed(host string, allowed []string) bool {
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func checkBasicAuth(secret *corev1.Secret) []compliancev1alpha1.Violation {
+	var found []compliancev1alpha1.Violation
+	for _, key := range []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey} {
+		if len(secret.Data[key]) == 0 {
+			found = append(found, newViolation(RuleBasicAuth, "data."+key, "basic-auth Secret has no %s", key))
+		}
+	}
+	return found
+}
+
+func checkSSHAuth(secret *corev1.Secret, spec *compliancev1alpha1.PayloadSpec) []compliancev1alpha1.Violation {
+	field := "data." + corev1.SSHAuthPrivateKey
+	alg, err := sshKeyAlgorithm(secret.Data[corev1.SSHAuthPrivateKey])
+	if err != nil {
+		return []compliancev1alpha1.Violation{newViolation(RuleSSHAuth, field, "private key cannot be parsed: %v", err)}
+	}
+	if len(spec.AllowedSSHKeyAlgorithms) > 0 && !slices.Contains(spec.AllowedSSHKeyAlgorithms, alg) {
+		return []compliancev1alpha1.Violation{newViolation(RuleSSHAuth, field, "private key algorithm %s is not allowed", alg)}
+	}
+	return nil
+}
+
+// sshKeyAlgorithm parses an SSH private key in any format ssh-keygen writes.
+// The algorithm of a passphrase protected key is read from its public part.
+func sshKeyAlgorithm(data []byte) (compliancev1alpha1.SSHKeyAlgorithm, error) {
+	if len(data) == 0 {
+		return "", errors.New("key is missing or empty")
+	}
+
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) && missing.PublicKey != nil {
+			return sshPublicKeyAlgorithm(missing.PublicKey.Type())
+		}
+		return "", err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return "", err
+	}
+	return sshPublicKeyAlgorithm(signer.PublicKey().Type())
+}
+
+func sshPublicKeyAlgorithm(keyType string) (compliancev1alpha1.SSHKeyAlgorithm, error) {
+	switch {
+	case keyType == ssh.KeyAlgoRSA:
+		return compliancev1alpha1.SSHKeyAlgorithmRSA, nil
+	case strings.HasPrefix(keyType, "ecdsa-"):
+		return compliancev1alpha1.SSHKeyAlgorithmECDSA, nil
+	case keyType == ssh.KeyAlgoED25519:
+		return compliancev1alpha1.SSHKeyAlgorithmEd25519, nil
+	case keyType == "ssh-dss":
+		return compliancev1alpha1.SSHKeyAlgorithmDSA, nil
+	}
+	return "", fmt.Errorf("unsupported key type %s", keyType)
+}
+
+// checkServiceAccountToken checks the ServiceAccount reference of a token
+// Secret. Without a reader only the reference itself is checked.
+func checkServiceAccountToken(ctx context.Context, secret *corev1.Secret, reader client.Reader) []compliancev1alpha1.Violation {
+	field := "metadata.annotations." + corev1.ServiceAccountNameKey
+	name := secret.Annotations[corev1.ServiceAccountNameKey]
+	if name == "" {
+		return []compliancev1alpha1.Violation{newViolation(RuleServiceAccountToken, field, "token Secret does not name a ServiceAccount")}
+	}
+	if reader == nil {
+		return nil
+	}
+
+	var sa corev1.ServiceAccount
+	err := reader.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: name}, &sa)
+	switch {
+	case apierrors.IsNotFound(err):
+		return []compliancev1alpha1.Violation{newViolation(RuleServiceAccountToken, field, "ServiceAccount %s does not exist", name)}
+	case err != nil:
+		// Like a failing CEL rule, a failed lookup must not pass silently
+		return []compliancev1alpha1.Violation{newViolation(RuleServiceAccountToken, field, "ServiceAccount %s cannot be verified: %v", name, err)}
+	}
+
+	if uid := secret.Annotations[corev1.ServiceAccountUIDKey]; uid != "" && uid != string(sa.UID) {
+		return []compliancev1alpha1.Violation{newViolation(RuleServiceAccountToken, "metadata.annotations."+corev1.ServiceAccountUIDKey,
+			"token belongs to a previous ServiceAccount named %s", name)}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Payload checks", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "apps"},
+			Data:       map[string][]byte{},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{
+					string(corev1.SecretTypeOpaque),
+					string(corev1.SecretTypeDockercfg),
+					string(corev1.SecretTypeDockerConfigJson),
+					string(corev1.SecretTypeBasicAuth),
+					string(corev1.SecretTypeSSHAuth),
+					string(corev1.SecretTypeServiceAccountToken),
+				},
+				Payload: &compliancev1alpha1.PayloadSpec{},
+			},
+		}
+	})
+
+	messages := func(opts ...Option) []string {
+		out := []string{}
+		for _, v := range CheckSecretAgainstPolicy(secret, policy, opts...) {
+			out = append(out, v.RuleID+": "+v.Message)
+		}
+		return out
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:token"))
+
+	It("Should pass Secrets of types without payload rules", func() {
+		secret.Type = corev1.SecretTypeOpaque
+		Expect(messages()).To(BeEmpty())
+	})
+
+	Context("When checking pull Secrets", func() {
+		BeforeEach(func() {
+			secret.Type = corev1.SecretTypeDockerConfigJson
+		})
+
+		It("Should report configs that do not parse or hold no credentials", func() {
+			Expect(messages()).To(Equal([]string{"payload.dockerConfig: .dockerconfigjson is invalid: key is missing or empty"}))
+
+			secret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths": {}}`)
+			Expect(messages()).To(Equal([]string{"payload.dockerConfig: .dockerconfigjson is invalid: no registry credentials found"}))
+
+			secret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":`)
+			Expect(messages()).To(HaveLen(1))
+		})
+
+		It("Should report malformed credentials per registry", func() {
+			secret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths": {
+				"ghcr.io": {"auth": "` + auth + `"},
+				"quay.io": {"username": "robot"},
+				"registry.local": {"auth": "bm9jb2xvbg=="}
+			}}`)
+			Expect(messages()).To(Equal([]string{
+				"payload.dockerConfig: credentials for registry quay.io are invalid: auth or username and password are required",
+				"payload.dockerConfig: credentials for registry registry.local are invalid: auth must encode username:password",
+			}))
+		})
+
+		It("Should only allow the listed registries", func() {
+			policy.Spec.Payload.AllowedRegistries = []string{"ghcr.io", "*.dkr.ecr.eu-west-1.amazonaws.com", "registry.local:5000"}
+			secret.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths": {
+				"GHCR.io": {"auth": "` + auth + `"},
+				"https://index.docker.io/v1/": {"auth": "` + auth + `"},
+				"123456789012.dkr.ecr.eu-west-1.amazonaws.com": {"auth": "` + auth + `"},
+				"registry.local:5000/team": {"auth": "` + auth + `"},
+				"registry.local": {"auth": "` + auth + `"}
+			}}`)
+			Expect(messages()).To(Equal([]string{
+				"payload.registry: registry https://index.docker.io/v1/ is not allowed",
+				"payload.registry: registry registry.local is not allowed",
+			}))
+		})
+
+		It("Should read legacy dockercfg Secrets", func() {
+			secret.Type = corev1.SecretTypeDockercfg
+			policy.Spec.Payload.AllowedRegistries = []string{"ghcr.io"}
+			secret.Data[corev1.DockerConfigKey] = []byte(`{"quay.io": {"auth": "` + auth + `"}}`)
+			Expect(messages()).To(Equal([]string{"payload.registry: registry quay.io is not allowed"}))
+		})
+	})
+
+	It("Should require username and password in basic-auth Secrets", func() {
+		secret.Type = corev1.SecretTypeBasicAuth
+		secret.Data[corev1.BasicAuthUsernameKey] = []byte("admin")
+		Expect(messages()).To(Equal([]string{"payload.basicAuth: basic-auth Secret has no password"}))
+	})
+
+	Context("When checking ssh-auth Secrets", func() {
+		BeforeEach(func() {
+			secret.Type = corev1.SecretTypeSSHAuth
+		})
+
+		It("Should parse OpenSSH and PEM keys and enforce the allowed algorithms", func() {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			block, err := ssh.MarshalPrivateKey(edKey, "")
+			Expect(err).NotTo(HaveOccurred())
+			secret.Data[corev1.SSHAuthPrivateKey] = pem.EncodeToMemory(block)
+			Expect(messages()).To(BeEmpty())
+
+			policy.Spec.Payload.AllowedSSHKeyAlgorithms = []compliancev1alpha1.SSHKeyAlgorithm{compliancev1alpha1.SSHKeyAlgorithmEd25519}
+			Expect(messages()).To(BeEmpty())
+
+			ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalECPrivateKey(ecKey)
+			Expect(err).NotTo(HaveOccurred())
+			secret.Data[corev1.SSHAuthPrivateKey] = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+			Expect(messages()).To(Equal([]string{"payload.sshAuth: private key algorithm ecdsa is not allowed"}))
+		})
+
+		It("Should read the algorithm of passphrase protected keys", func() {
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			block, err := ssh.MarshalPrivateKeyWithPassphrase(edKey, "", []byte("passphrase"))
+			Expect(err).NotTo(HaveOccurred())
+			secret.Data[corev1.SSHAuthPrivateKey] = pem.EncodeToMemory(block)
+
+			policy.Spec.Payload.AllowedSSHKeyAlgorithms = []compliancev1alpha1.SSHKeyAlgorithm{compliancev1alpha1.SSHKeyAlgorithmRSA}
+			Expect(messages()).To(Equal([]string{"payload.sshAuth: private key algorithm ed25519 is not allowed"}))
+		})
+
+		It("Should report keys that cannot be parsed", func() {
+			secret.Data[corev1.SSHAuthPrivateKey] = []byte("not a key")
+			Expect(messages()).To(ConsistOf(HavePrefix("payload.sshAuth: private key cannot be parsed: ")))
+		})
+	})
+
+	Context("When checking service account tokens", func() {
+		var sa *corev1.ServiceAccount
+
+		BeforeEach(func() {
+			secret.Type = corev1.SecretTypeServiceAccountToken
+			secret.Annotations = map[string]string{corev1.ServiceAccountNameKey: "builder"}
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "apps", UID: "uid-1"}}
+		})
+
+		reader := func(objs ...client.Object) Option {
+			return WithReader(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build())
+		}
+
+		It("Should require the ServiceAccount annotation even without a reader", func() {
+			Expect(messages()).To(BeEmpty())
+
+			delete(secret.Annotations, corev1.ServiceAccountNameKey)
+			Expect(messages()).To(Equal([]string{"payload.serviceAccountToken: token Secret does not name a ServiceAccount"}))
+		})
+
+		It("Should require the ServiceAccount to exist with a matching UID", func() {
+			Expect(messages(reader())).To(Equal([]string{"payload.serviceAccountToken: ServiceAccount builder does not exist"}))
+			Expect(messages(reader(sa))).To(BeEmpty())
+
+			secret.Annotations[corev1.ServiceAccountUIDKey] = "uid-0"
+			Expect(messages(reader(sa))).To(Equal([]string{
+				"payload.serviceAccountToken: token belongs to a previous ServiceAccount named builder",
+			}))
+		})
+
+		It("Should report failed lookups instead of passing", func() {
+			failing := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return errors.New("connection refused")
+				},
+			}).Build()
+			Expect(messages(WithReader(failing))).To(Equal([]string{
+				"payload.serviceAccountToken: ServiceAccount builder cannot be verified: connection refused",
+			}))
+		})
+	})
+
+	It("Should list the payload rules as active", func() {
+		policy.Spec.Payload.AllowedRegistries = []string{"ghcr.io"}
+		Expect(ActiveRules(policy)).To(Equal([]string{
+			RuleAllowedTypes, RuleDockerConfig, RuleBasicAuth, RuleSSHAuth, RuleServiceAccountToken, RuleRegistry,
+		}))
+	})
+})
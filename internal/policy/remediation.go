@@ -0,0 +1,102 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+const (
+	// LastRotatedAnnotation holds the RFC 3339 time the Secret data last changed.
+	LastRotatedAnnotation = "lastRotated"
+
+	// MutationsAnnotation records, as a JSON list, the changes the mutating
+	// webhook made to a Secret on its last admission.
+	MutationsAnnotation = "compliance.security.local/mutations"
+)
+
+// Remediate applies the remediation block of the policy to secret in place and
+// returns a description of every change it made. old is the Secret before an
+// update and nil on create.
+func Remediate(secret, old *corev1.Secret, policy compliancev1alpha1.PolicyObject, now time.Time) []string {
+	spec := policy.GetSpec()
+	r := spec.Remediation
+	if r == nil {
+		return nil
+	}
+
+	var changes []string
+
+	for _, k := range sortedKeys(r.Labels) {
+		if _, ok := secret.Labels[k]; ok {
+			continue
+		}
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[k] = r.Labels[k]
+		changes = append(changes, fmt.Sprintf("added label %s=%s", k, r.Labels[k]))
+	}
+
+	for _, k := range sortedKeys(r.Annotations) {
+		if _, ok := secret.Annotations[k]; ok {
+			continue
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[k] = r.Annotations[k]
+		changes = append(changes, fmt.Sprintf("added annotation %s=%s", k, r.Annotations[k]))
+	}
+
+	if r.StripDisallowedKeys {
+		for _, key := range spec.DisallowedKeys {
+			_, inData := secret.Data[key]
+			_, inStringData := secret.StringData[key]
+			if !inData && !inStringData {
+				continue
+			}
+			delete(secret.Data, key)
+			delete(secret.StringData, key)
+			changes = append(changes, fmt.Sprintf("removed disallowed key %s", key))
+		}
+	}
+
+	// The type of an existing Secret is immutable
+	if r.DefaultType != "" && old == nil && secret.Type != corev1.SecretType(r.DefaultType) &&
+		(secret.Type == "" || (secret.Type == corev1.SecretTypeOpaque && !isIn(corev1.SecretTypeOpaque, spec.AllowedTypes))) {
+		secret.Type = corev1.SecretType(r.DefaultType)
+		changes = append(changes, fmt.Sprintf("set type to %s", r.DefaultType))
+	}
+
+	// Stamp after stripping keys so that removed keys do not count as a change
+	if r.StampLastRotated && (old == nil || !dataEqual(old.Data, secret.Data)) {
+		stamp := now.UTC().Format(time.RFC3339)
+		if secret.Annotations[LastRotatedAnnotation] != stamp {
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[LastRotatedAnnotation] = stamp
+			changes = append(changes, fmt.Sprintf("set annotation %s=%s", LastRotatedAnnotation, stamp))
+		}
+	}
+
+	return changes
+}
+
+func dataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		w, ok := b[k]
+		if !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
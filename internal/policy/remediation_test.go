@@ -0,0 +1,109 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Remediate", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db",
+				Namespace: "apps",
+				Labels:    map[string]string{"owner": "team-a"},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{"username": []byte("admin"), "password": []byte("hunter2")},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys: []string{"password"},
+			},
+		}
+	})
+
+	It("Should do nothing without a remediation block", func() {
+		Expect(Remediate(secret, nil, policy, now)).To(BeEmpty())
+		Expect(secret.Data).To(HaveKey("password"))
+	})
+
+	It("Should add missing labels and annotations without overwriting existing ones", func() {
+		policy.Spec.Remediation = &compliancev1alpha1.RemediationSpec{
+			Labels:      map[string]string{"owner": "platform", "managed": "true"},
+			Annotations: map[string]string{"team": "payments"},
+		}
+
+		Expect(Remediate(secret, nil, policy, now)).To(ConsistOf(
+			"added label managed=true",
+			"added annotation team=payments",
+		))
+		Expect(secret.Labels).To(Equal(map[string]string{"owner": "team-a", "managed": "true"}))
+		Expect(secret.Annotations).To(HaveKeyWithValue("team", "payments"))
+	})
+
+	It("Should strip disallowed keys", func() {
+		policy.Spec.Remediation = &compliancev1alpha1.RemediationSpec{StripDisallowedKeys: true}
+
+		Expect(Remediate(secret, nil, policy, now)).To(ConsistOf("removed disallowed key password"))
+		Expect(secret.Data).NotTo(HaveKey("password"))
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should only set the default type on create when the type is not allowed", func() {
+		policy.Spec.AllowedTypes = []string{"example.com/db"}
+		policy.Spec.Remediation = &compliancev1alpha1.RemediationSpec{DefaultType: "example.com/db"}
+
+		old := secret.DeepCopy()
+		Expect(Remediate(secret, old, policy, now)).To(BeEmpty())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeOpaque))
+
+		Expect(Remediate(secret, nil, policy, now)).To(ConsistOf("set type to example.com/db"))
+		Expect(secret.Type).To(Equal(corev1.SecretType("example.com/db")))
+	})
+
+	It("Should stamp lastRotated on create and when data changes", func() {
+		policy.Spec.Remediation = &compliancev1alpha1.RemediationSpec{StampLastRotated: true}
+
+		Expect(Remediate(secret, nil, policy, now)).To(HaveLen(1))
+		Expect(secret.Annotations).To(HaveKeyWithValue(LastRotatedAnnotation, "2026-01-02T03:04:05Z"))
+
+		old := secret.DeepCopy()
+		later := now.Add(time.Hour)
+		Expect(Remediate(secret, old, policy, later)).To(BeEmpty(), "unchanged data must not be stamped")
+
+		secret.Data["password"] = []byte("rotated")
+		Expect(Remediate(secret, old, policy, later)).To(HaveLen(1))
+		Expect(secret.Annotations).To(HaveKeyWithValue(LastRotatedAnnotation, "2026-01-02T04:04:05Z"))
+	})
+})
@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Operator-owned annotations recording when the Secret data last changed.
+// Unlike lastRotated they are maintained by the webhook and the controller
+// and are only trusted while the recorded hash matches the current data.
+const (
+	DataHashAnnotation  = "compliance.security.local/data-hash"
+	RotatedAtAnnotation = "compliance.security.local/rotated-at"
+)
+
+// Prefixes of the digests stored in DataHashAnnotation. Earlier releases
+// stored an unkeyed SHA-256 digest.
+const (
+	dataHashPrefix       = "hmac-sha256:"
+	legacyDataHashPrefix = "sha256:"
+)
+
+// dataHashKey keys DataHash. Offline tools that never store records may
+// leave it empty.
+var dataHashKey []byte
+
+// SetDataHashKey sets the key of the digests DataHash returns. It must be
+// called before any Secret is tracked, and every replica of the operator has
+// to use the same key.
+func SetDataHashKey(key []byte) {
+	dataHashKey = key
+}
+
+// DataHash returns a stable HMAC-SHA256 digest of the Secret data, keyed by
+// the operator's key. The digest is visible to anyone who can read Secret
+// metadata; without the key it cannot be used to guess the values offline.
+func DataHash(data map[string][]byte) string {
+	return dataHashPrefix + hex.EncodeToString(digest(hmac.New(sha256.New, dataHashKey), data))
+}
+
+// legacyDataHash returns the unkeyed digest earlier releases stored.
+func legacyDataHash(data map[string][]byte) string {
+	return legacyDataHashPrefix + hex.EncodeToString(digest(sha256.New(), data))
+}
+
+func digest(h hash.Hash, data map[string][]byte) []byte {
+	for _, k := range sortedKeys(data) {
+		// Length-prefix keys and values so that different maps never collide
+		writeField(h, []byte(k))
+		writeField(h, data[k])
+	}
+	return h.Sum(nil)
+}
+
+func writeField(h hash.Hash, b []byte) {
+	_ = binary.Write(h, binary.BigEndian, uint64(len(b)))
+	_, _ = h.Write(b)
+}
+
+// RotatedAt returns the time the Secret data was last changed according to
+// the operator's record. ok is false when there is no record or the record
+// does not match the current data.
+func RotatedAt(secret *corev1.Secret) (t time.Time, ok bool) {
+	if !hmac.Equal([]byte(secret.Annotations[DataHashAnnotation]), []byte(DataHash(secret.Data))) {
+		return time.Time{}, false
+	}
+	return recordedTime(secret)
+}
+
+// recordedRotation is RotatedAt, but also trusts a legacy unkeyed record
+// that matches the data, so that upgrading does not reset rotation times.
+func recordedRotation(secret *corev1.Secret) (t time.Time, ok bool) {
+	if t, ok := RotatedAt(secret); ok {
+		return t, true
+	}
+	recorded := secret.Annotations[DataHashAnnotation]
+	if !strings.HasPrefix(recorded, legacyDataHashPrefix) || recorded != legacyDataHash(secret.Data) {
+		return time.Time{}, false
+	}
+	return recordedTime(secret)
+}
+
+func recordedTime(secret *corev1.Secret) (t time.Time, ok bool) {
+	t, err := time.Parse(time.RFC3339, secret.Annotations[RotatedAtAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// TrackRotation updates the rotation record of a Secret passing through
+// admission and reports whether the annotations changed. old is the Secret
+// before an update and nil on create.
+//
+// A new Secret or changed data is stamped with now. When the data is
+// unchanged the previous record is carried over, so that editing the
+// annotations by hand cannot fake a rotation.
+func TrackRotation(secret, old *corev1.Secret, now time.Time) bool {
+	hash := DataHash(secret.Data)
+	rotatedAt := now
+
+	if old != nil && dataEqual(old.Data, secret.Data) {
+		if t, ok := recordedRotation(old); ok {
+			rotatedAt = t
+		} else {
+			rotatedAt = untrackedRotationTime(old, now)
+		}
+	}
+	return setRotationRecord(secret, hash, rotatedAt)
+}
+
+// EnsureRotationRecord creates or repairs the rotation record of a stored
+// Secret and reports whether the annotations changed. A Secret that was never
+// tracked is assumed unrotated since creation; a Secret whose data changed
+// without passing the webhook is stamped with now. A legacy record is
+// replaced by a keyed one that keeps its time.
+func EnsureRotationRecord(secret *corev1.Secret, now time.Time) bool {
+	if _, ok := RotatedAt(secret); ok {
+		return false
+	}
+	rotatedAt, ok := recordedRotation(secret)
+	if !ok {
+		rotatedAt = untrackedRotationTime(secret, now)
+	}
+	return setRotationRecord(secret, DataHash(secret.Data), rotatedAt)
+}
+
+func untrackedRotationTime(secret *corev1.Secret, now time.Time) time.Time {
+	if secret.Annotations[DataHashAnnotation] == "" && !secret.CreationTimestamp.IsZero() {
+		return secret.CreationTimestamp.Time
+	}
+	return now
+}
+
+func setRotationRecord(secret *corev1.Secret, hash string, rotatedAt time.Time) bool {
+	stamp := rotatedAt.UTC().Format(time.RFC3339)
+	if secret.Annotations[DataHashAnnotation] == hash && secret.Annotations[RotatedAtAnnotation] == stamp {
+		return false
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[DataHashAnnotation] = hash
+	secret.Annotations[RotatedAtAnnotation] = stamp
+	return true
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Rotation tracking", func() {
+	var (
+		secret  *corev1.Secret
+		created time.Time
+		now     time.Time
+	)
+
+	rotatedAt := func() time.Time {
+		t, ok := RotatedAt(secret)
+		Expect(ok).To(BeTrue(), "expected a trusted rotation record")
+		return t
+	}
+
+	BeforeEach(func() {
+		created = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "db",
+				Namespace:         "apps",
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Data: map[string][]byte{"password": []byte("hunter2")},
+		}
+	})
+
+	It("Should hash data independently of map order and detect changes", func() {
+		a := DataHash(map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+		Expect(DataHash(map[string][]byte{"b": []byte("2"), "a": []byte("1")})).To(Equal(a))
+		Expect(DataHash(map[string][]byte{"a": []byte("12")})).NotTo(Equal(DataHash(map[string][]byte{"a1": []byte("2")})))
+		Expect(DataHash(map[string][]byte{"a": []byte("1"), "b": []byte("3")})).NotTo(Equal(a))
+	})
+
+	It("Should key the hash so that it cannot be recomputed without the key", func() {
+		data := map[string][]byte{"password": []byte("hunter2")}
+		withoutKey := DataHash(data)
+		Expect(withoutKey).To(HavePrefix("hmac-sha256:"))
+		Expect(withoutKey).NotTo(Equal(legacyDataHash(data)))
+
+		DeferCleanup(SetDataHashKey, dataHashKey)
+		SetDataHashKey([]byte("0123456789abcdef0123456789abcdef"))
+		Expect(DataHash(data)).NotTo(Equal(withoutKey))
+	})
+
+	It("Should stamp new Secrets and ignore a supplied record", func() {
+		secret.Annotations = map[string]string{
+			DataHashAnnotation:  DataHash(secret.Data),
+			RotatedAtAnnotation: "2030-01-01T00:00:00Z",
+		}
+
+		Expect(TrackRotation(secret, nil, now)).To(BeTrue())
+		Expect(rotatedAt()).To(Equal(now))
+	})
+
+	It("Should carry the record over when data is unchanged", func() {
+		TrackRotation(secret, nil, created)
+		old := secret.DeepCopy()
+
+		secret.Annotations[RotatedAtAnnotation] = now.Format(time.RFC3339)
+		Expect(TrackRotation(secret, old, now)).To(BeTrue())
+		Expect(rotatedAt()).To(Equal(created))
+	})
+
+	It("Should restamp when data changes", func() {
+		TrackRotation(secret, nil, created)
+		old := secret.DeepCopy()
+
+		secret.Data["password"] = []byte("rotated")
+		Expect(TrackRotation(secret, old, now)).To(BeTrue())
+		Expect(rotatedAt()).To(Equal(now))
+	})
+
+	It("Should not trust a record whose hash does not match the data", func() {
+		TrackRotation(secret, nil, now)
+		secret.Data["password"] = []byte("changed-behind-our-back")
+
+		_, ok := RotatedAt(secret)
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("EnsureRotationRecord", func() {
+		It("Should assume untracked Secrets are unrotated since creation", func() {
+			Expect(EnsureRotationRecord(secret, now)).To(BeTrue())
+			Expect(rotatedAt()).To(Equal(created))
+			Expect(EnsureRotationRecord(secret, now)).To(BeFalse())
+		})
+
+		It("Should replace a legacy record without resetting its time", func() {
+			secret.Annotations = map[string]string{
+				DataHashAnnotation:  legacyDataHash(secret.Data),
+				RotatedAtAnnotation: created.Format(time.RFC3339),
+			}
+			_, ok := RotatedAt(secret)
+			Expect(ok).To(BeFalse())
+
+			Expect(EnsureRotationRecord(secret, now)).To(BeTrue())
+			Expect(secret.Annotations[DataHashAnnotation]).To(Equal(DataHash(secret.Data)))
+			Expect(rotatedAt()).To(Equal(created))
+		})
+
+		It("Should restamp Secrets whose data changed outside admission", func() {
+			TrackRotation(secret, nil, created)
+			secret.Data["password"] = []byte("rotated")
+
+			Expect(EnsureRotationRecord(secret, now)).To(BeTrue())
+			Expect(rotatedAt()).To(Equal(now))
+		})
+	})
+
+	It("Should evaluate the rotation rule against the operator record", func() {
+		policy := &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{""},
+				Rotation:     compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30},
+			},
+		}
+
+		// A hand-set lastRotated annotation is not trusted
+		secret.Annotations = map[string]string{LastRotatedAnnotation: time.Now().Format(time.RFC3339)}
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(ConsistOf(
+			HaveField("RuleID", RuleRotation),
+		))
+
+		TrackRotation(secret, nil, time.Now())
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+})
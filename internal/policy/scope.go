@@ -0,0 +1,50 @@
+package policy
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// SecretInScope reports whether a Secret is governed by the policy. A namespaced
+// SecretPolicy only ever applies to Secrets in its own namespace; both kinds are
+// further narrowed by their namespaceSelector and secretSelector. nsLabels are
+// the labels of the Secret's namespace and are only consulted when a
+// namespaceSelector is set.
+func SecretInScope(secret *corev1.Secret, nsLabels map[string]string, policy compliancev1alpha1.PolicyObject) (bool, error) {
+	if ns := policy.GetNamespace(); ns != "" && ns != secret.Namespace {
+		return false, nil
+	}
+
+	spec := policy.GetSpec()
+	ok, err := selectorMatches(spec.NamespaceSelector, nsLabels)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	ok, err = selectorMatches(spec.SecretSelector, secret.Labels)
+	if err != nil {
+		return false, fmt.Errorf("invalid secretSelector: %w", err)
+	}
+	return ok, nil
+}
+
+// selectorMatches treats a nil selector as matching everything, unlike
+// metav1.LabelSelectorAsSelector which maps nil to labels.Nothing().
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return sel.Matches(labels.Set(set)), nil
+}
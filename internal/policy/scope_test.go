@@ -0,0 +1,95 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("SecretInScope", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db",
+				Namespace: "team-a",
+				Labels:    map[string]string{"app": "db"},
+			},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{}
+	})
+
+	It("Should select every Secret when no selectors are set", func() {
+		Expect(SecretInScope(secret, nil, policy)).To(BeTrue())
+	})
+
+	It("Should limit a namespaced policy to its own namespace", func() {
+		policy.Namespace = "team-b"
+		Expect(SecretInScope(secret, nil, policy)).To(BeFalse())
+
+		policy.Namespace = "team-a"
+		Expect(SecretInScope(secret, nil, policy)).To(BeTrue())
+	})
+
+	It("Should let a cluster policy select Secrets in any namespace", func() {
+		cluster := &compliancev1alpha1.ClusterSecretPolicy{}
+		Expect(SecretInScope(secret, nil, cluster)).To(BeTrue())
+	})
+
+	It("Should filter on namespace labels", func() {
+		policy.Spec.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "a"},
+		}
+		Expect(SecretInScope(secret, map[string]string{"team": "a"}, policy)).To(BeTrue())
+		Expect(SecretInScope(secret, map[string]string{"team": "b"}, policy)).To(BeFalse())
+		Expect(SecretInScope(secret, nil, policy)).To(BeFalse())
+	})
+
+	It("Should filter on Secret labels", func() {
+		policy.Spec.SecretSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "app",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"db", "cache"},
+			}},
+		}
+		Expect(SecretInScope(secret, nil, policy)).To(BeTrue())
+
+		secret.Labels["app"] = "web"
+		Expect(SecretInScope(secret, nil, policy)).To(BeFalse())
+	})
+
+	It("Should report an invalid selector", func() {
+		policy.Spec.SecretSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "app",
+				Operator: "Bogus",
+			}},
+		}
+		_, err := SecretInScope(secret, nil, policy)
+		Expect(err).To(MatchError(ContainSubstring("invalid secretSelector")))
+	})
+})
@@ -1,63 +1,521 @@
 package policy
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
 )
 
-func CheckSecretAgainstPolicy(secret *corev1.Secret, policy *compliancev1alpha1.SecretPolicy) []error {
-	var errs []error
+// Rule identifiers attached to every violation. They are stable and are
+// referenced by SecretPolicyException.spec.rules.
+const (
+	RuleAllowedTypes      = "allowedTypes"
+	RuleDisallowedKeys    = "disallowedKeys"
+	RuleBase64            = "encryption.base64"
+	RuleExternalKMS       = "encryption.externalKMS"
+	RuleAllowedNamespaces = "accessRules.allowedNamespaces"
+	RuleConsumers         = "accessRules.consumers"
+	RuleRBAC              = "accessRules.rbac"
+	RuleRotation          = "rotation"
+	RuleCredentials       = "content.credentials"
+	RuleEntropy           = "content.entropy"
+	RuleWeakPassword      = "content.weakPassword"
 
-	if !isIn(secret.Type, policy.Spec.AllowedTypes) {
-		errs = append(errs, fmt.Errorf("secret type %s not allowed", secret.Type))
+	RuleAllowedKeyPatterns    = "allowedKeyPatterns"
+	RuleDisallowedKeyPatterns = "disallowedKeyPatterns"
+	RuleMaxKeys               = "maxKeys"
+	RuleMaxValueBytes         = "maxValueBytes"
+	RuleMaxTotalBytes         = "maxTotalBytes"
+
+	RuleTLSCertificate        = "tls.certificate"
+	RuleTLSKeyPair            = "tls.keyPair"
+	RuleTLSExpiry             = "tls.expiry"
+	RuleTLSKeySize            = "tls.keySize"
+	RuleTLSSignatureAlgorithm = "tls.signatureAlgorithm"
+	RuleTLSSAN                = "tls.san"
+	RuleTLSChain              = "tls.chain"
+
+	RuleDockerConfig        = "payload.dockerConfig"
+	RuleRegistry            = "payload.registry"
+	RuleBasicAuth           = "payload.basicAuth"
+	RuleSSHAuth             = "payload.sshAuth"
+	RuleServiceAccountToken = "payload.serviceAccountToken"
+
+	RuleUnused = "unused"
+)
+
+// ruleInfo holds the default severity and remediation hint of a rule.
+type ruleInfo struct {
+	severity    compliancev1alpha1.Severity
+	remediation string
+}
+
+var ruleCatalog = map[string]ruleInfo{
+	RuleAllowedTypes: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "use one of the Secret types listed in allowedTypes",
+	},
+	RuleDisallowedKeys: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "remove the key or move its value to an approved secret store",
+	},
+	RuleAllowedKeyPatterns: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "rename the key to match one of the patterns in allowedKeyPatterns",
+	},
+	RuleDisallowedKeyPatterns: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "remove the key or move its value to an approved secret store",
+	},
+	RuleMaxKeys: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "split the Secret into several smaller Secrets",
+	},
+	RuleMaxValueBytes: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "store large files in a volume or an object store instead of a Secret",
+	},
+	RuleMaxTotalBytes: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "store large files in a volume or an object store instead of a Secret, or split the Secret",
+	},
+	RuleBase64: {
+		severity:    compliancev1alpha1.SeverityLow,
+		remediation: "store the value base64 encoded",
+	},
+	RuleExternalKMS: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "encrypt the Secret with the external KMS and set the kms-encrypted annotation",
+	},
+	RuleAllowedNamespaces: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "move the Secret to one of the allowed namespaces",
+	},
+	RuleConsumers: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "run the workload as an allowed ServiceAccount, or stop it from consuming the Secret",
+	},
+	RuleRBAC: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "restrict the binding's role with resourceNames, or bind it to an allowed ServiceAccount only",
+	},
+	RuleRotation: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "rotate the Secret data",
+	},
+	RuleCredentials: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "move the credential to a Secret of the matching type or an approved secret store, and rotate it",
+	},
+	RuleEntropy: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "move the value to a dedicated Secret, or list the key in contentScan.ignoreKeys if it is not sensitive",
+	},
+	RuleWeakPassword: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "replace the password with a long, randomly generated one",
+	},
+	RuleTLSCertificate: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "store the PEM encoded certificate chain, leaf first, in tls.crt",
+	},
+	RuleTLSKeyPair: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "store the PEM encoded private key of the certificate in tls.key",
+	},
+	RuleTLSExpiry: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "renew the certificate",
+	},
+	RuleTLSKeySize: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "reissue the certificate with a larger key",
+	},
+	RuleTLSSignatureAlgorithm: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "reissue the certificate with a SHA-256 or stronger signature",
+	},
+	RuleTLSSAN: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "reissue the certificate with the required subject alternative names",
+	},
+	RuleTLSChain: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "append the intermediate certificates to tls.crt",
+	},
+	RuleDockerConfig: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "regenerate the pull Secret with kubectl create secret docker-registry",
+	},
+	RuleRegistry: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "only store pull credentials for the registries listed in payload.allowedRegistries",
+	},
+	RuleBasicAuth: {
+		severity:    compliancev1alpha1.SeverityMedium,
+		remediation: "set both username and password",
+	},
+	RuleSSHAuth: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "store a private key of an allowed algorithm in ssh-privatekey",
+	},
+	RuleServiceAccountToken: {
+		severity:    compliancev1alpha1.SeverityHigh,
+		remediation: "delete token Secrets of removed ServiceAccounts, or create the ServiceAccount first",
+	},
+	RuleUnused: {
+		severity:    compliancev1alpha1.SeverityLow,
+		remediation: "delete the Secret if it is no longer needed, or list its type in unused.ignoredTypes",
+	},
+}
+
+// newViolation builds a Violation using the catalog defaults for the rule.
+func newViolation(rule, field, format string, args ...any) compliancev1alpha1.Violation {
+	info := ruleCatalog[rule]
+	severity := info.severity
+	if severity == "" {
+		severity = compliancev1alpha1.SeverityMedium
 	}
 
-	for key := range secret.Data {
-		if contains(policy.Spec.DisallowedKeys, key) {
-			errs = append(errs, fmt.Errorf("key %s is disallowed", key))
-		}
+	return compliancev1alpha1.Violation{
+		RuleID:      rule,
+		Severity:    severity,
+		Field:       field,
+		Message:     fmt.Sprintf(format, args...),
+		Remediation: info.remediation,
 	}
+}
+
+// Option configures CheckSecretAgainstPolicy.
+type Option func(*options)
 
-	if policy.Spec.Encryption.EnforceBase64 {
-		mode := policy.Spec.Encryption.Base64Mode
-		if mode == "" {
-			mode = "relaxed"
+type options struct {
+	reader         client.Reader
+	accessAnalysis bool
+	access         *AccessIndex
+	references     *SecretReferences
+}
+
+// WithReader lets rules look up the objects a Secret refers to, such as the
+// ServiceAccount of a token Secret. Without a reader those lookups are
+// skipped, e.g. when evaluating manifests offline.
+func WithReader(reader client.Reader) Option {
+	return func(o *options) {
+		o.reader = reader
+	}
+}
+
+// WithAccessAnalysis enables the accessRules.allowedServiceAccounts rules,
+// which need WithAccessIndex, and the unused rule, which needs WithReferences.
+// They report on Pods, RBAC objects and other referrers rather than on the
+// Secret itself, so only the controller's scans enable them; admission never
+// rejects a Secret because of who can read it or whether it is used.
+func WithAccessAnalysis() Option {
+	return func(o *options) {
+		o.accessAnalysis = true
+	}
+}
+
+// WithAccessIndex sets the index the accessRules.allowedServiceAccounts
+// rules look consumers and RBAC grants up in. Sharing one AccessIndex across
+// the Secrets of a scan lists the Pods and RBAC objects of each namespace
+// once.
+func WithAccessIndex(idx *AccessIndex) Option {
+	return func(o *options) {
+		o.access = idx
+	}
+}
+
+// WithReferences sets the references the unused rule looks Secrets up in.
+// Sharing one SecretReferences across the Secrets of a scan lists the
+// referrers of each namespace once.
+func WithReferences(refs *SecretReferences) Option {
+	return func(o *options) {
+		o.references = refs
+	}
+}
+
+// CheckSecretAgainstPolicy evaluates a Secret against every rule of the policy
+// and returns one Violation per finding.
+func CheckSecretAgainstPolicy(
+	secret *corev1.Secret,
+	policy compliancev1alpha1.PolicyObject,
+	opts ...Option,
+) []compliancev1alpha1.Violation {
+	return CheckSecretAgainstPolicyContext(context.Background(), secret, policy, opts...)
+}
+
+// CheckSecretAgainstPolicyContext is CheckSecretAgainstPolicy with a span for
+// the evaluation and a child span for each rule the policy evaluates.
+func CheckSecretAgainstPolicyContext(
+	ctx context.Context,
+	secret *corev1.Secret,
+	policy compliancev1alpha1.PolicyObject,
+	opts ...Option,
+) []compliancev1alpha1.Violation {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, span := tracing.Start(ctx, "CheckSecretAgainstPolicy",
+		tracing.KeyPolicy.String(PolicyRef(policy)),
+		tracing.KeySecret.String(secret.Namespace+"/"+secret.Name),
+	)
+	defer span.End()
+
+	var errs []compliancev1alpha1.Violation
+	spec := policy.GetSpec()
+
+	errs = append(errs, checkRule(ctx, RuleAllowedTypes, func() []compliancev1alpha1.Violation {
+		if !isIn(secret.Type, spec.AllowedTypes) {
+			return []compliancev1alpha1.Violation{newViolation(RuleAllowedTypes, "type", "secret type %s not allowed", secret.Type)}
 		}
+		return nil
+	})...)
+
+	if len(spec.DisallowedKeys) > 0 {
+		errs = append(errs, checkRule(ctx, RuleDisallowedKeys, func() []compliancev1alpha1.Violation {
+			var found []compliancev1alpha1.Violation
+			for key := range secret.Data {
+				if contains(spec.DisallowedKeys, key) {
+					found = append(found, newViolation(RuleDisallowedKeys, "data."+key, "key %s is disallowed", key))
+				}
+			}
+			return found
+		})...)
+	}
+
+	if len(spec.AllowedKeyPatterns) > 0 {
+		errs = append(errs, checkRule(ctx, RuleAllowedKeyPatterns, func() []compliancev1alpha1.Violation {
+			return checkAllowedKeyPatterns(secret, spec.AllowedKeyPatterns)
+		})...)
+	}
 
-		for key, val := range secret.Data {
-			fmt.Printf("[SecretPolicy] Validating key=%s mode=%s valueLen=%d\n",
-				key, mode, len(val))
+	if len(spec.DisallowedKeyPatterns) > 0 {
+		errs = append(errs, checkRule(ctx, RuleDisallowedKeyPatterns, func() []compliancev1alpha1.Violation {
+			return checkDisallowedKeyPatterns(secret, spec.DisallowedKeyPatterns)
+		})...)
+	}
 
-			if !isValidBase64(val, mode) {
-				errs = append(errs, fmt.Errorf("key %s is not valid base64 (%s mode)", key, mode))
+	if spec.MaxKeys > 0 {
+		errs = append(errs, checkRule(ctx, RuleMaxKeys, func() []compliancev1alpha1.Violation {
+			if n := len(secret.Data); n > spec.MaxKeys {
+				return []compliancev1alpha1.Violation{newViolation(RuleMaxKeys, "data", "secret has %d keys, at most %d are allowed", n, spec.MaxKeys)}
 			}
-		}
+			return nil
+		})...)
 	}
 
-	if policy.Spec.Encryption.ExternalKMS {
-		if secret.Annotations["kms-encrypted"] != "true" {
-			errs = append(errs, fmt.Errorf("secret is not encrypted via external KMS"))
-		}
+	if spec.MaxValueBytes > 0 {
+		errs = append(errs, checkRule(ctx, RuleMaxValueBytes, func() []compliancev1alpha1.Violation {
+			return checkMaxValueBytes(secret, spec.MaxValueBytes)
+		})...)
+	}
+
+	if spec.MaxTotalBytes > 0 {
+		errs = append(errs, checkRule(ctx, RuleMaxTotalBytes, func() []compliancev1alpha1.Violation {
+			if size := dataSize(secret); size > spec.MaxTotalBytes {
+				return []compliancev1alpha1.Violation{newViolation(RuleMaxTotalBytes, "data", "secret data is %d bytes, at most %d are allowed", size, spec.MaxTotalBytes)}
+			}
+			return nil
+		})...)
 	}
 
-	if !contains(policy.Spec.AccessRules.AllowedNamespaces, secret.Namespace) {
-		errs = append(errs, fmt.Errorf("namespace %s is not allowed", secret.Namespace))
+	if spec.Encryption.EnforceBase64 {
+		errs = append(errs, checkRule(ctx, RuleBase64, func() []compliancev1alpha1.Violation {
+			mode := spec.Encryption.Base64Mode
+			if mode == "" {
+				mode = "relaxed"
+			}
+
+			var found []compliancev1alpha1.Violation
+			for key, val := range secret.Data {
+				if !isValidBase64(val, mode) {
+					found = append(found, newViolation(RuleBase64, "data."+key, "key %s is not valid base64 (%s mode)", key, mode))
+				}
+			}
+			return found
+		})...)
+	}
+
+	if spec.Encryption.ExternalKMS {
+		errs = append(errs, checkRule(ctx, RuleExternalKMS, func() []compliancev1alpha1.Violation {
+			if secret.Annotations["kms-encrypted"] != "true" {
+				return []compliancev1alpha1.Violation{newViolation(RuleExternalKMS, "metadata.annotations.kms-encrypted", "secret is not encrypted via external KMS")}
+			}
+			return nil
+		})...)
 	}
 
-	if policy.Spec.Rotation.Enabled {
-		if isRotationExpired(secret, policy.Spec.Rotation.IntervalDays) {
-			errs = append(errs, fmt.Errorf("secret rotation interval exceeded"))
+	// An empty list places no restriction; scoping is done with selectors
+	if len(spec.AccessRules.AllowedNamespaces) > 0 {
+		errs = append(errs, checkRule(ctx, RuleAllowedNamespaces, func() []compliancev1alpha1.Violation {
+			if !contains(spec.AccessRules.AllowedNamespaces, secret.Namespace) {
+				return []compliancev1alpha1.Violation{newViolation(RuleAllowedNamespaces, "metadata.namespace", "namespace %s is not allowed", secret.Namespace)}
+			}
+			return nil
+		})...)
+	}
+
+	if len(spec.AccessRules.AllowedServiceAccounts) > 0 && o.accessAnalysis && o.access != nil {
+		errs = append(errs, checkRule(ctx, RuleConsumers, func() []compliancev1alpha1.Violation {
+			return checkConsumers(ctx, o.access, secret, spec.AccessRules.AllowedServiceAccounts)
+		})...)
+		errs = append(errs, checkRule(ctx, RuleRBAC, func() []compliancev1alpha1.Violation {
+			return checkRBACAccess(ctx, o.access, secret, spec.AccessRules.AllowedServiceAccounts)
+		})...)
+	}
+
+	if spec.Rotation.Enabled {
+		errs = append(errs, checkRule(ctx, RuleRotation, func() []compliancev1alpha1.Violation {
+			if isRotationExpired(secret, spec.Rotation.IntervalDays) {
+				return []compliancev1alpha1.Violation{newViolation(RuleRotation, "metadata.annotations."+RotatedAtAnnotation, "secret rotation interval exceeded")}
+			}
+			return nil
+		})...)
+	}
+
+	if scan := spec.ContentScan; scan != nil {
+		if scan.Credentials != nil {
+			errs = append(errs, checkRule(ctx, RuleCredentials, func() []compliancev1alpha1.Violation {
+				return scanCredentials(secret, scan)
+			})...)
+		}
+		if scan.Entropy != nil {
+			errs = append(errs, checkRule(ctx, RuleEntropy, func() []compliancev1alpha1.Violation {
+				return scanEntropy(secret, scan)
+			})...)
 		}
+		if scan.WeakPasswords != nil {
+			errs = append(errs, checkRule(ctx, RuleWeakPassword, func() []compliancev1alpha1.Violation {
+				return scanWeakPasswords(secret, scan)
+			})...)
+		}
+	}
+
+	// The TLS rules share one parse of the certificate and run in one span
+	if spec.TLS != nil {
+		errs = append(errs, checkRule(ctx, "tls", func() []compliancev1alpha1.Violation {
+			return checkTLS(secret, spec.TLS, time.Now())
+		})...)
 	}
 
+	// Only the payload rule of the Secret's type applies
+	if spec.Payload != nil {
+		errs = append(errs, checkRule(ctx, "payload", func() []compliancev1alpha1.Violation {
+			return checkPayload(ctx, secret, spec.Payload, o)
+		})...)
+	}
+
+	if spec.Unused != nil && o.accessAnalysis && o.references != nil {
+		errs = append(errs, checkRule(ctx, RuleUnused, func() []compliancev1alpha1.Violation {
+			return checkUnused(ctx, secret, spec.Unused, o.references, time.Now())
+		})...)
+	}
+
+	errs = append(errs, checkCELRules(ctx, secret, policy)...)
+
+	span.SetAttributes(tracing.KeyViolations.Int(len(errs)))
 	return errs
 }
 
+// checkRule runs one rule's check in its own span.
+func checkRule(ctx context.Context, rule string, check func() []compliancev1alpha1.Violation) []compliancev1alpha1.Violation {
+	_, span := tracing.Start(ctx, "EvaluateRule", tracing.KeyRule.String(rule))
+	defer span.End()
+
+	found := check()
+	span.SetAttributes(tracing.KeyViolations.Int(len(found)))
+	return found
+}
+
+// ActiveRules returns the identifiers of the rules the policy evaluates, in
+// the order CheckSecretAgainstPolicy checks them with the same opts. A Secret
+// that has no violation for one of these rules passes it.
+func ActiveRules(policy compliancev1alpha1.PolicyObject, opts ...Option) []string {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	spec := policy.GetSpec()
+
+	rules := []string{RuleAllowedTypes}
+	if len(spec.DisallowedKeys) > 0 {
+		rules = append(rules, RuleDisallowedKeys)
+	}
+	if len(spec.AllowedKeyPatterns) > 0 {
+		rules = append(rules, RuleAllowedKeyPatterns)
+	}
+	if len(spec.DisallowedKeyPatterns) > 0 {
+		rules = append(rules, RuleDisallowedKeyPatterns)
+	}
+	if spec.MaxKeys > 0 {
+		rules = append(rules, RuleMaxKeys)
+	}
+	if spec.MaxValueBytes > 0 {
+		rules = append(rules, RuleMaxValueBytes)
+	}
+	if spec.MaxTotalBytes > 0 {
+		rules = append(rules, RuleMaxTotalBytes)
+	}
+	if spec.Encryption.EnforceBase64 {
+		rules = append(rules, RuleBase64)
+	}
+	if spec.Encryption.ExternalKMS {
+		rules = append(rules, RuleExternalKMS)
+	}
+	if len(spec.AccessRules.AllowedNamespaces) > 0 {
+		rules = append(rules, RuleAllowedNamespaces)
+	}
+	if len(spec.AccessRules.AllowedServiceAccounts) > 0 && o.accessAnalysis {
+		rules = append(rules, RuleConsumers, RuleRBAC)
+	}
+	if spec.Rotation.Enabled {
+		rules = append(rules, RuleRotation)
+	}
+	if scan := spec.ContentScan; scan != nil {
+		if scan.Credentials != nil {
+			rules = append(rules, RuleCredentials)
+		}
+		if scan.Entropy != nil {
+			rules = append(rules, RuleEntropy)
+		}
+		if scan.WeakPasswords != nil {
+			rules = append(rules, RuleWeakPassword)
+		}
+	}
+	if spec.TLS != nil {
+		rules = append(rules, RuleTLSCertificate, RuleTLSKeyPair, RuleTLSExpiry, RuleTLSKeySize, RuleTLSSignatureAlgorithm)
+		if len(spec.TLS.RequiredSANs) > 0 {
+			rules = append(rules, RuleTLSSAN)
+		}
+		if spec.TLS.VerifyChain {
+			rules = append(rules, RuleTLSChain)
+		}
+	}
+	if spec.Payload != nil {
+		rules = append(rules, RuleDockerConfig, RuleBasicAuth, RuleSSHAuth, RuleServiceAccountToken)
+		if len(spec.Payload.AllowedRegistries) > 0 {
+			rules = append(rules, RuleRegistry)
+		}
+	}
+	if spec.Unused != nil && o.accessAnalysis {
+		rules = append(rules, RuleUnused)
+	}
+	for _, rule := range spec.Rules {
+		rules = append(rules, RuleCELPrefix+rule.Name)
+	}
+	return rules
+}
+
 func isIn(value corev1.SecretType, list []string) bool {
 	for _, v := range list {
 		if string(value) == v {
@@ -96,20 +554,17 @@ func isValidBase64Relaxed(data []byte) bool {
 func isValidBase64Strict(data []byte) bool {
 	for _, b := range data {
 		if b >= 32 && b <= 126 {
-			fmt.Printf("[StrictMode] Rejecting plaintext-looking value: %q\n", string(data))
 			return false
 		}
 	}
 	return true // looks encoded or binary
 }
 
+// isRotationExpired checks the operator-owned rotation record. A Secret
+// without a trustworthy record is treated as overdue.
 func isRotationExpired(secret *corev1.Secret, intervalDays int) bool {
-	last := secret.Annotations["lastRotated"]
-	if last == "" {
-		return true
-	}
-	t, err := time.Parse(time.RFC3339, last)
-	if err != nil {
+	t, ok := RotatedAt(secret)
+	if !ok {
 		return true
 	}
 	return time.Since(t).Hours() > float64(intervalDays*24)
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
+)
+
+var _ = Describe("CheckSecretAgainstPolicy", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"username": []byte("admin")},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeOpaque)},
+			},
+		}
+	})
+
+	It("Should return no violations for a compliant Secret", func() {
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should report structured violations with rule, severity and field", func() {
+		policy.Spec.DisallowedKeys = []string{"username"}
+		policy.Spec.AllowedTypes = []string{string(corev1.SecretTypeTLS)}
+
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"RuleID":      Equal(RuleAllowedTypes),
+				"Severity":    Equal(compliancev1alpha1.SeverityMedium),
+				"Field":       Equal("type"),
+				"Message":     Equal("secret type Opaque not allowed"),
+				"Remediation": Not(BeEmpty()),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"RuleID":   Equal(RuleDisallowedKeys),
+				"Severity": Equal(compliancev1alpha1.SeverityHigh),
+				"Field":    Equal("data.username"),
+			}),
+		))
+	})
+
+	It("Should only restrict namespaces when allowedNamespaces is set", func() {
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+
+		policy.Spec.AccessRules.AllowedNamespaces = []string{"prod"}
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(ConsistOf(
+			HaveField("RuleID", RuleAllowedNamespaces),
+		))
+	})
+
+	It("Should trace the evaluation with a child span per active rule", func() {
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(otel.SetTracerProvider, previous)
+
+		policy.UID = "traced-policy"
+		DeferCleanup(ForgetPolicy, policy.UID)
+		policy.Spec.DisallowedKeys = []string{"username"}
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{{Name: "has-keys", Expression: "size(secret.keys) > 0"}}
+		CheckSecretAgainstPolicyContext(context.Background(), secret, policy)
+
+		var parent sdktrace.ReadOnlySpan
+		rules := map[string]int64{}
+		for _, span := range recorder.Ended() {
+			if span.Name() == "CheckSecretAgainstPolicy" {
+				parent = span
+				continue
+			}
+			Expect(span.Name()).To(Equal("EvaluateRule"))
+			var rule string
+			var violations int64
+			for _, attr := range span.Attributes() {
+				switch attr.Key {
+				case tracing.KeyRule:
+					rule = attr.Value.AsString()
+				case tracing.KeyViolations:
+					violations = attr.Value.AsInt64()
+				}
+			}
+			rules[rule] = violations
+		}
+
+		Expect(parent).NotTo(BeNil())
+		Expect(parent.Attributes()).To(ContainElement(tracing.KeyViolations.Int(1)))
+		Expect(rules).To(Equal(map[string]int64{
+			RuleAllowedTypes:           0,
+			RuleDisallowedKeys:         1,
+			RuleCELPrefix + "has-keys": 0,
+		}))
+		for _, span := range recorder.Ended() {
+			if span != parent {
+				Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			}
+		}
+	})
+})
+
+var _ = Describe("ActiveRules", func() {
+	It("Should list only the rules the policy configures", func() {
+		policy := &compliancev1alpha1.SecretPolicy{}
+		Expect(ActiveRules(policy)).To(Equal([]string{RuleAllowedTypes}))
+
+		policy.Spec.DisallowedKeys = []string{"password"}
+		policy.Spec.Rotation.Enabled = true
+		policy.Spec.Rules = []compliancev1alpha1.CELRule{{Name: "has-owner"}}
+		Expect(ActiveRules(policy)).To(Equal([]string{
+			RuleAllowedTypes, RuleDisallowedKeys, RuleRotation, RuleCELPrefix + "has-owner",
+		}))
+	})
+})
@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Defaults of the TLS rules.
+const (
+	defaultMinRSAKeySize   = 2048
+	defaultMinECDSAKeySize = 256
+)
+
+// defaultForbiddenSignatureAlgorithms are the algorithms broken enough that
+// no CA should still use them.
+var defaultForbiddenSignatureAlgorithms = []x509.SignatureAlgorithm{
+	x509.MD2WithRSA,
+	x509.MD5WithRSA,
+	x509.SHA1WithRSA,
+	x509.DSAWithSHA1,
+	x509.ECDSAWithSHA1,
+}
+
+// IsSignatureAlgorithm reports whether name is a signature algorithm known to
+// crypto/x509, for validating TLSSpec.ForbiddenSignatureAlgorithms.
+func IsSignatureAlgorithm(name string) bool {
+	for alg := x509.MD2WithRSA; alg <= x509.PureEd25519; alg++ {
+		if alg.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCertificates decodes every CERTIFICATE block of a PEM bundle.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// CertificateExpiry returns when the leaf certificate of a TLS Secret
+// expires. ok is false for other Secrets and unparsable certificates.
+func CertificateExpiry(secret *corev1.Secret) (time.Time, bool) {
+	if secret.Type != corev1.SecretTypeTLS {
+		return time.Time{}, false
+	}
+	certs, err := ParseCertificates(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return certs[0].NotAfter, true
+}
+
+// checkTLS runs the TLS rules against a kubernetes.io/tls Secret. A
+// certificate that cannot be parsed is reported once and ends the checks.
+func checkTLS(secret *corev1.Secret, spec *compliancev1alpha1.TLSSpec, now time.Time) []compliancev1alpha1.Violation {
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil
+	}
+
+	certPEM := secret.Data[corev1.TLSCertKey]
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return []compliancev1alpha1.Violation{newViolation(RuleTLSCertificate, "data."+corev1.TLSCertKey,
+			"certificate cannot be parsed: %v", err)}
+	}
+	leaf := certs[0]
+
+	var found []compliancev1alpha1.Violation
+	if _, err := tls.X509KeyPair(certPEM, secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		found = append(found, newViolation(RuleTLSKeyPair, "data."+corev1.TLSPrivateKeyKey,
+			"private key is invalid or does not match the certificate: %v", err))
+	}
+
+	expired := now.After(leaf.NotAfter)
+	switch {
+	case expired:
+		v := newViolation(RuleTLSExpiry, "data."+corev1.TLSCertKey, "certificate expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+		v.Severity = compliancev1alpha1.SeverityCritical
+		found = append(found, v)
+	case spec.MinDaysToExpiry > 0 && leaf.NotAfter.Before(now.AddDate(0, 0, spec.MinDaysToExpiry)):
+		found = append(found, newViolation(RuleTLSExpiry, "data."+corev1.TLSCertKey,
+			"certificate expires on %s, within %d days", leaf.NotAfter.UTC().Format(time.RFC3339), spec.MinDaysToExpiry))
+	}
+
+	if msg := weakKey(leaf, spec); msg != "" {
+		found = append(found, newViolation(RuleTLSKeySize, "data."+corev1.TLSCertKey, "%s", msg))
+	}
+
+	for i, cert := range certs {
+		// The signature of a root is never checked by clients
+		if i > 0 && isSelfSigned(cert) {
+			continue
+		}
+		if isForbiddenAlgorithm(cert.SignatureAlgorithm, spec.ForbiddenSignatureAlgorithms) {
+			found = append(found, newViolation(RuleTLSSignatureAlgorithm, "data."+corev1.TLSCertKey,
+				"certificate %q is signed with forbidden algorithm %s", cert.Subject.CommonName, cert.SignatureAlgorithm))
+		}
+	}
+
+	for _, pattern := range spec.RequiredSANs {
+		re, err := CompileKeyPattern(pattern)
+		if err != nil {
+			found = append(found, newViolation(RuleTLSSAN, "data."+corev1.TLSCertKey, "invalid SAN pattern %q: %v", pattern, err))
+			continue
+		}
+		if !hasMatchingSAN(leaf, re) {
+			found = append(found, newViolation(RuleTLSSAN, "data."+corev1.TLSCertKey, "no subject alternative name matches %q", pattern))
+		}
+	}
+
+	// An expired leaf fails verification too, but is already reported
+	if spec.VerifyChain && !expired {
+		if err := verifyChain(secret, spec, certs, now); err != nil {
+			found = append(found, newViolation(RuleTLSChain, "data."+corev1.TLSCertKey, "certificate chain is incomplete or untrusted: %v", err))
+		}
+	}
+
+	return found
+}
+
+// weakKey describes why the public key of cert is too small, or returns "".
+func weakKey(cert *x509.Certificate, spec *compliancev1alpha1.TLSSpec) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		minSize := spec.MinRSAKeySize
+		if minSize == 0 {
+			minSize = defaultMinRSAKeySize
+		}
+		if size := key.N.BitLen(); size < minSize {
+			return fmt.Sprintf("RSA key has %d bits, at least %d are required", size, minSize)
+		}
+	case *ecdsa.PublicKey:
+		minSize := spec.MinECDSAKeySize
+		if minSize == 0 {
+			minSize = defaultMinECDSAKeySize
+		}
+		if size := key.Curve.Params().BitSize; size < minSize {
+			return fmt.Sprintf("ECDSA key has %d bits, at least %d are required", size, minSize)
+		}
+	}
+	return ""
+}
+
+func isForbiddenAlgorithm(alg x509.SignatureAlgorithm, forbidden []string) bool {
+	if len(forbidden) == 0 {
+		for _, f := range defaultForbiddenSignatureAlgorithms {
+			if alg == f {
+				return true
+			}
+		}
+		return false
+	}
+	return contains(forbidden, alg.String())
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+func hasMatchingSAN(cert *x509.Certificate, re *regexp.Regexp) bool {
+	for _, name := range cert.DNSNames {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	for _, ip := range cert.IPAddresses {
+		if re.MatchString(ip.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyChain verifies the leaf against the trusted roots, using only the
+// intermediates in tls.crt.
+func verifyChain(secret *corev1.Secret, spec *compliancev1alpha1.TLSSpec, certs []*x509.Certificate, now time.Time) error {
+	opts := x509.VerifyOptions{
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	bundle := []byte(spec.CABundle)
+	if len(bundle) == 0 {
+		bundle = secret.Data["ca.crt"]
+	}
+	// Without a bundle, nil roots select the system roots
+	if len(bundle) > 0 {
+		roots, err := ParseCertificates(bundle)
+		if err != nil {
+			return fmt.Errorf("CA bundle: %w", err)
+		}
+		opts.Roots = x509.NewCertPool()
+		for _, root := range roots {
+			opts.Roots.AddCert(root)
+		}
+	}
+
+	_, err := certs[0].Verify(opts)
+	return err
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("TLS checks", func() {
+	var (
+		rootKey, interKey, leafKey *ecdsa.PrivateKey
+		root, inter, leaf          *x509.Certificate
+		secret                     *corev1.Secret
+		policy                     *compliancev1alpha1.SecretPolicy
+	)
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		return key
+	}
+
+	issue := func(template *x509.Certificate, pub any, parent *x509.Certificate, signer crypto.Signer) *x509.Certificate {
+		if template.SerialNumber == nil {
+			template.SerialNumber = big.NewInt(time.Now().UnixNano())
+		}
+		if template.NotBefore.IsZero() {
+			template.NotBefore = time.Now().Add(-time.Hour)
+		}
+		if template.NotAfter.IsZero() {
+			template.NotAfter = time.Now().AddDate(0, 0, 90)
+		}
+		if parent == nil {
+			parent = template
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+		Expect(err).NotTo(HaveOccurred())
+		cert, err := x509.ParseCertificate(der)
+		Expect(err).NotTo(HaveOccurred())
+		return cert
+	}
+
+	ca := func(name string) *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: name}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	}
+
+	encode := func(certs ...*x509.Certificate) []byte {
+		var out []byte
+		for _, c := range certs {
+			out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})...)
+		}
+		return out
+	}
+
+	encodeKey := func(key crypto.Signer) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+
+	leafTemplate := func() *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: "app"}, DNSNames: []string{"app.example.com"}}
+	}
+
+	useLeaf := func(cert *x509.Certificate, key crypto.Signer, chain ...*x509.Certificate) {
+		secret.Data[corev1.TLSCertKey] = encode(append([]*x509.Certificate{cert}, chain...)...)
+		secret.Data[corev1.TLSPrivateKeyKey] = encodeKey(key)
+	}
+
+	rules := func() []string {
+		var out []string
+		for _, v := range CheckSecretAgainstPolicy(secret, policy) {
+			out = append(out, v.RuleID)
+		}
+		return out
+	}
+
+	BeforeEach(func() {
+		rootKey, interKey, leafKey = newKey(), newKey(), newKey()
+		root = issue(ca("root"), &rootKey.PublicKey, nil, rootKey)
+		inter = issue(ca("intermediate"), &interKey.PublicKey, root, rootKey)
+		leaf = issue(leafTemplate(), &leafKey.PublicKey, inter, interKey)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "apps"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{},
+		}
+		useLeaf(leaf, leafKey, inter)
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeTLS), string(corev1.SecretTypeOpaque)},
+				TLS: &compliancev1alpha1.TLSSpec{
+					MinDaysToExpiry: 30,
+					RequiredSANs:    []string{`.*\.example\.com`},
+					VerifyChain:     true,
+					CABundle:        string(encode(root)),
+				},
+			},
+		}
+	})
+
+	It("Should pass a valid certificate with a complete chain", func() {
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should skip Secrets that are not of type kubernetes.io/tls", func() {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data[corev1.TLSCertKey] = []byte("garbage")
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should only report an unparsable certificate once", func() {
+		secret.Data[corev1.TLSCertKey] = []byte("garbage")
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleTLSCertificate))
+		Expect(found[0].Message).To(Equal("certificate cannot be parsed: no PEM encoded certificate found"))
+	})
+
+	It("Should report a private key that does not match the certificate", func() {
+		secret.Data[corev1.TLSPrivateKeyKey] = encodeKey(newKey())
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleTLSKeyPair))
+		Expect(found[0].Field).To(Equal("data.tls.key"))
+	})
+
+	It("Should report expired certificates as critical without a chain finding", func() {
+		template := leafTemplate()
+		template.NotBefore = time.Now().AddDate(0, 0, -10)
+		template.NotAfter = time.Now().AddDate(0, 0, -1)
+		useLeaf(issue(template, &leafKey.PublicKey, inter, interKey), leafKey, inter)
+
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleTLSExpiry))
+		Expect(found[0].Severity).To(Equal(compliancev1alpha1.SeverityCritical))
+		Expect(found[0].Message).To(HavePrefix("certificate expired on "))
+	})
+
+	It("Should report certificates expiring within minDaysToExpiry", func() {
+		policy.Spec.TLS.MinDaysToExpiry = 120
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleTLSExpiry))
+		Expect(found[0].Severity).To(Equal(compliancev1alpha1.SeverityHigh))
+		Expect(found[0].Message).To(HaveSuffix("within 120 days"))
+	})
+
+	It("Should report small keys and forbidden signature algorithms", func() {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+		template := leafTemplate()
+		template.SignatureAlgorithm = x509.SHA1WithRSA
+		self := issue(template, &rsaKey.PublicKey, nil, rsaKey)
+		useLeaf(self, rsaKey)
+		policy.Spec.TLS.VerifyChain = false
+
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(2))
+		Expect(found[0].RuleID).To(Equal(RuleTLSKeySize))
+		Expect(found[0].Message).To(Equal("RSA key has 1024 bits, at least 2048 are required"))
+		Expect(found[1].RuleID).To(Equal(RuleTLSSignatureAlgorithm))
+		Expect(found[1].Message).To(Equal(`certificate "app" is signed with forbidden algorithm SHA1-RSA`))
+
+		policy.Spec.TLS.MinRSAKeySize = 1024
+		Expect(rules()).To(Equal([]string{RuleTLSSignatureAlgorithm}))
+	})
+
+	It("Should use the configured signature algorithms and exempt self-signed roots", func() {
+		policy.Spec.TLS.ForbiddenSignatureAlgorithms = []string{x509.ECDSAWithSHA256.String()}
+		useLeaf(leaf, leafKey, inter, root)
+
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(2))
+		Expect(found[0].Message).To(ContainSubstring(`"app"`))
+		Expect(found[1].Message).To(ContainSubstring(`"intermediate"`))
+	})
+
+	It("Should match required SANs against the whole name", func() {
+		policy.Spec.TLS.RequiredSANs = []string{`example\.com`, `app\..*`}
+		found := CheckSecretAgainstPolicy(secret, policy)
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].RuleID).To(Equal(RuleTLSSAN))
+		Expect(found[0].Message).To(Equal(`no subject alternative name matches "example\\.com"`))
+	})
+
+	It("Should report an incomplete chain and fall back to the ca.crt key", func() {
+		useLeaf(leaf, leafKey)
+		Expect(rules()).To(Equal([]string{RuleTLSChain}))
+
+		policy.Spec.TLS.CABundle = ""
+		secret.Data["ca.crt"] = encode(inter)
+		Expect(CheckSecretAgainstPolicy(secret, policy)).To(BeEmpty())
+	})
+
+	It("Should expose the expiry of the leaf certificate", func() {
+		expiry, ok := CertificateExpiry(secret)
+		Expect(ok).To(BeTrue())
+		Expect(expiry).To(Equal(leaf.NotAfter))
+
+		secret.Type = corev1.SecretTypeOpaque
+		_, ok = CertificateExpiry(secret)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should list the TLS rules as active", func() {
+		policy.Spec.TLS.RequiredSANs = nil
+		Expect(ActiveRules(policy)).To(Equal([]string{
+			RuleAllowedTypes, RuleTLSCertificate, RuleTLSKeyPair, RuleTLSExpiry, RuleTLSKeySize,
+			RuleTLSSignatureAlgorithm, RuleTLSChain,
+		}))
+	})
+
+	It("Should recognise signature algorithm names", func() {
+		Expect(IsSignatureAlgorithm("SHA1-RSA")).To(BeTrue())
+		Expect(IsSignatureAlgorithm("Ed25519")).To(BeTrue())
+		Expect(IsSignatureAlgorithm("SHA1")).To(BeFalse())
+	})
+})
@@ -0,0 +1,432 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// Operator-owned metadata of unused Secrets. UnusedSinceAnnotation records
+// when the controller first found the Secret unreferenced; CleanupLabel is
+// set on reported Secrets when the policy asks for it.
+const (
+	UnusedSinceAnnotation = "compliance.security.local/unused-since"
+	CleanupLabel          = "compliance.security.local/cleanup"
+	CleanupLabelUnused    = "unused"
+)
+
+// defaultUnusedAfterDays applies when UnusedSpec.AfterDays is not set.
+const defaultUnusedAfterDays = 30
+
+// helmReleaseType is the type of the Secrets Helm stores releases in. They
+// are read by Helm through the API and never referenced.
+const helmReleaseType corev1.SecretType = "helm.sh/release.v1"
+
+// customReference is a field of a custom resource that names a Secret in
+// the resource's namespace. A "[]" segment of path iterates over a list.
+type customReference struct {
+	gvk  schema.GroupVersionKind
+	path []string
+	// defaultToName means an unset field defaults to the resource's name
+	defaultToName bool
+	// crossNamespace means a sibling "namespace" field may name a Secret in
+	// another namespace
+	crossNamespace bool
+}
+
+// customReferences are the references of well-known custom resources. Kinds
+// that are not installed are skipped.
+var customReferences = []customReference{
+	{
+		gvk:  schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"},
+		path: []string{"spec", "secretName"},
+	},
+	{
+		gvk:           schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1", Kind: "ExternalSecret"},
+		path:          []string{"spec", "target", "name"},
+		defaultToName: true,
+	},
+	{
+		gvk:           schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1beta1", Kind: "ExternalSecret"},
+		path:          []string{"spec", "target", "name"},
+		defaultToName: true,
+	},
+	{
+		gvk:            schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"},
+		path:           []string{"spec", "listeners", "[]", "tls", "certificateRefs", "[]", "name"},
+		crossNamespace: true,
+	},
+}
+
+// SecretReferences answers whether anything references a Secret. The
+// references of a namespace are collected on first use and reused for the
+// lifetime of the value, so one SecretReferences serves one scan. It is safe
+// for concurrent use, and namespaces are collected concurrently.
+type SecretReferences struct {
+	reader     client.Reader
+	namespaces namespaceCache[map[string]bool]
+	// crossNamespace holds the "namespace/name" of Secrets referenced from
+	// other namespaces. It is collected once, under the key "".
+	crossNamespace namespaceCache[map[string]bool]
+}
+
+// NewSecretReferences returns a SecretReferences that lists referencing
+// objects through reader, page by page. reader should read from the API
+// server: listing through a cache would keep an informer for every
+// referencing kind running.
+func NewSecretReferences(reader client.Reader) *SecretReferences {
+	return &SecretReferences{reader: reader}
+}
+
+// Referenced reports whether the Secret is referenced by a Pod, a workload's
+// Pod template, a ServiceAccount, an Ingress or a known custom resource in
+// its namespace, or by a Gateway in another namespace. Token Secrets are
+// referenced by their ServiceAccount, and Secrets owned by a controller are
+// managed and count as referenced too.
+func (r *SecretReferences) Referenced(ctx context.Context, secret *corev1.Secret) (bool, error) {
+	if metav1.GetControllerOf(secret) != nil {
+		return true, nil
+	}
+
+	names, err := r.namespaces.get(secret.Namespace, func() (map[string]bool, error) {
+		return r.collect(ctx, secret.Namespace)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if secret.Type == corev1.SecretTypeServiceAccountToken {
+		return names[serviceAccountKey(secret.Annotations[corev1.ServiceAccountNameKey])], nil
+	}
+	if names[secret.Name] {
+		return true, nil
+	}
+
+	crossNamespace, err := r.crossNamespace.get("", func() (map[string]bool, error) {
+		return r.collectCrossNamespace(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+	return crossNamespace[secret.Namespace+"/"+secret.Name], nil
+}
+
+// serviceAccountKey records an existing ServiceAccount among the referenced
+// Secret names. Secret names cannot contain a slash, so keys never collide.
+func serviceAccountKey(name string) string {
+	return "serviceaccount/" + name
+}
+
+// collect returns the names of the Secrets referenced in namespace.
+func (r *SecretReferences) collect(ctx context.Context, namespace string) (map[string]bool, error) {
+	names := map[string]bool{}
+	add := func(name, _ string) {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	inNamespace := client.InNamespace(namespace)
+
+	var pods corev1.PodList
+	if err := listPaged(ctx, r.reader, &pods, func() error {
+		for i := range pods.Items {
+			visitSecretRefs(&pods.Items[i].Spec, add)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return nil, err
+	}
+
+	if err := visitPodTemplates(ctx, r.reader, inNamespace, func(_ string, spec *corev1.PodSpec) {
+		visitSecretRefs(spec, add)
+	}); err != nil {
+		return nil, err
+	}
+
+	var serviceAccounts corev1.ServiceAccountList
+	if err := listPaged(ctx, r.reader, &serviceAccounts, func() error {
+		for _, sa := range serviceAccounts.Items {
+			names[serviceAccountKey(sa.Name)] = true
+			for _, ref := range sa.Secrets {
+				add(ref.Name, "")
+			}
+			for _, ref := range sa.ImagePullSecrets {
+				add(ref.Name, "")
+			}
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return nil, err
+	}
+
+	var ingresses networkingv1.IngressList
+	if err := listPaged(ctx, r.reader, &ingresses, func() error {
+		for _, ing := range ingresses.Items {
+			for _, tls := range ing.Spec.TLS {
+				add(tls.SecretName, "")
+			}
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return nil, err
+	}
+
+	if err := visitCustomReferences(ctx, r.reader, func(ref customReference, item *unstructured.Unstructured) {
+		refs := fieldRefs(item.Object, ref.path)
+		if len(refs) == 0 && ref.defaultToName {
+			refs = []secretRef{{name: item.GetName()}}
+		}
+		for _, sr := range refs {
+			if !ref.crossNamespace || sr.namespace == "" || sr.namespace == namespace {
+				add(sr.name, "")
+			}
+		}
+	}, inNamespace); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// collectCrossNamespace returns the "namespace/name" of the Secrets that
+// custom resources reference in another namespace, across all namespaces.
+func (r *SecretReferences) collectCrossNamespace(ctx context.Context) (map[string]bool, error) {
+	keys := map[string]bool{}
+	err := visitCustomReferences(ctx, r.reader, func(ref customReference, item *unstructured.Unstructured) {
+		if !ref.crossNamespace {
+			return
+		}
+		for _, sr := range fieldRefs(item.Object, ref.path) {
+			if sr.namespace != "" && sr.namespace != item.GetNamespace() {
+				keys[sr.namespace+"/"+sr.name] = true
+			}
+		}
+	})
+	return keys, err
+}
+
+// visitCustomReferences calls visit for every installed custom resource of
+// the kinds in customReferences.
+func visitCustomReferences(
+	ctx context.Context,
+	reader client.Reader,
+	visit func(customReference, *unstructured.Unstructured),
+	opts ...client.ListOption,
+) error {
+	for _, ref := range customReferences {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(ref.gvk.GroupVersion().WithKind(ref.gvk.Kind + "List"))
+		if err := listPaged(ctx, reader, list, func() error {
+			for i := range list.Items {
+				visit(ref, &list.Items[i])
+			}
+			return nil
+		}, opts...); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("listing %s: %w", ref.gvk.Kind, err)
+		}
+	}
+	return nil
+}
+
+// visitPodTemplates calls visit for the Pod spec of every workload in a
+// namespace, along with the workload's kind and name.
+func visitPodTemplates(
+	ctx context.Context,
+	reader client.Reader,
+	inNamespace client.ListOption,
+	visit func(workload string, spec *corev1.PodSpec),
+) error {
+	var deployments appsv1.DeploymentList
+	if err := listPaged(ctx, reader, &deployments, func() error {
+		for i := range deployments.Items {
+			visit("Deployment "+deployments.Items[i].Name, &deployments.Items[i].Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return err
+	}
+
+	var replicaSets appsv1.ReplicaSetList
+	if err := listPaged(ctx, reader, &replicaSets, func() error {
+		for i := range replicaSets.Items {
+			visit("ReplicaSet "+replicaSets.Items[i].Name, &replicaSets.Items[i].Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return err
+	}
+
+	var statefulSets appsv1.StatefulSetList
+	if err := listPaged(ctx, reader, &statefulSets, func() error {
+		for i := range statefulSets.Items {
+			visit("StatefulSet "+statefulSets.Items[i].Name, &statefulSets.Items[i].Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return err
+	}
+
+	var daemonSets appsv1.DaemonSetList
+	if err := listPaged(ctx, reader, &daemonSets, func() error {
+		for i := range daemonSets.Items {
+			visit("DaemonSet "+daemonSets.Items[i].Name, &daemonSets.Items[i].Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return err
+	}
+
+	var jobs batchv1.JobList
+	if err := listPaged(ctx, reader, &jobs, func() error {
+		for i := range jobs.Items {
+			visit("Job "+jobs.Items[i].Name, &jobs.Items[i].Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace); err != nil {
+		return err
+	}
+
+	var cronJobs batchv1.CronJobList
+	return listPaged(ctx, reader, &cronJobs, func() error {
+		for i := range cronJobs.Items {
+			visit("CronJob "+cronJobs.Items[i].Name, &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+		}
+		return nil
+	}, inNamespace)
+}
+
+// secretRef is a reference of a custom resource to a Secret. namespace is
+// only set when the reference names one.
+type secretRef struct {
+	namespace string
+	name      string
+}
+
+// fieldRefs returns the references at path in obj, expanding "[]" segments
+// over every element of a list. The last segment is the field holding the
+// Secret's name, and a sibling "namespace" field its namespace.
+func fieldRefs(obj any, path []string) []secretRef {
+	if path[0] == "[]" {
+		list, _ := obj.([]any)
+		var out []secretRef
+		for _, item := range list {
+			out = append(out, fieldRefs(item, path[1:])...)
+		}
+		return out
+	}
+	m, ok := obj.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if len(path) > 1 {
+		return fieldRefs(m[path[0]], path[1:])
+	}
+
+	name, _ := m[path[0]].(string)
+	if name == "" {
+		return nil
+	}
+	namespace, _ := m["namespace"].(string)
+	return []secretRef{{namespace: namespace, name: name}}
+}
+
+// UnusedSince returns when the controller first found the Secret
+// unreferenced. ok is false when there is no valid record.
+func UnusedSince(secret *corev1.Secret) (t time.Time, ok bool) {
+	t, err := time.Parse(time.RFC3339, secret.Annotations[UnusedSinceAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// UsageTracked reports whether the unused rule of spec applies to the
+// Secret at all.
+func UsageTracked(secret *corev1.Secret, spec *compliancev1alpha1.UnusedSpec) bool {
+	return secret.Type != helmReleaseType && !isIn(secret.Type, spec.IgnoredTypes)
+}
+
+// TrackUsage creates or clears the unused-since record of a Secret and
+// reports whether the annotations changed. The record of a referenced
+// Secret is removed, so that the period starts over once it is unused again.
+func TrackUsage(secret *corev1.Secret, referenced bool, now time.Time) bool {
+	_, recorded := secret.Annotations[UnusedSinceAnnotation]
+	switch {
+	case referenced && recorded:
+		delete(secret.Annotations, UnusedSinceAnnotation)
+		return true
+	case !referenced && !recorded:
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[UnusedSinceAnnotation] = now.UTC().Format(time.RFC3339)
+		return true
+	}
+	return false
+}
+
+// Unused reports whether the Secret has been unreferenced for longer than
+// the policy allows, and since when. Secrets without a record are not
+// reported: the record is created by the controller before the Secret is
+// evaluated.
+func Unused(
+	ctx context.Context,
+	secret *corev1.Secret,
+	spec *compliancev1alpha1.UnusedSpec,
+	refs *SecretReferences,
+	now time.Time,
+) (since time.Time, unused bool, err error) {
+	if !UsageTracked(secret, spec) {
+		return time.Time{}, false, nil
+	}
+
+	referenced, err := refs.Referenced(ctx, secret)
+	if err != nil || referenced {
+		return time.Time{}, false, err
+	}
+
+	since, ok := UnusedSince(secret)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	days := spec.AfterDays
+	if days <= 0 {
+		days = defaultUnusedAfterDays
+	}
+	return since, !now.Before(since.AddDate(0, 0, days)), nil
+}
+
+// checkUnused reports a Secret that Unused finds unused, or a failed
+// reference lookup.
+func checkUnused(
+	ctx context.Context,
+	secret *corev1.Secret,
+	spec *compliancev1alpha1.UnusedSpec,
+	refs *SecretReferences,
+	now time.Time,
+) []compliancev1alpha1.Violation {
+	since, unused, err := Unused(ctx, secret, spec, refs, now)
+	if err != nil {
+		return []compliancev1alpha1.Violation{newViolation(RuleUnused, "", "references to the Secret cannot be determined: %v", err)}
+	}
+	if !unused {
+		return nil
+	}
+	return []compliancev1alpha1.Violation{newViolation(RuleUnused, "metadata.annotations."+UnusedSinceAnnotation,
+		"Secret has not been referenced by any Pod, workload, ServiceAccount, Ingress or known custom resource since %s",
+		since.UTC().Format(time.RFC3339))}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+var _ = Describe("Unused Secrets", func() {
+	var policy *compliancev1alpha1.SecretPolicy
+
+	BeforeEach(func() {
+		policy = &compliancev1alpha1.SecretPolicy{
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeOpaque), string(corev1.SecretTypeServiceAccountToken)},
+				Unused:       &compliancev1alpha1.UnusedSpec{AfterDays: 7},
+			},
+		}
+	})
+
+	secret := func(name string, unusedFor time.Duration) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"}, Type: corev1.SecretTypeOpaque}
+		if unusedFor > 0 {
+			s.Annotations = map[string]string{UnusedSinceAnnotation: time.Now().Add(-unusedFor).UTC().Format(time.RFC3339)}
+		}
+		return s
+	}
+
+	// The custom resources are served as unstructured objects of a mapper
+	// that knows their kinds
+	newReader := func(objs ...client.Object) client.Reader {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		for _, ref := range customReferences {
+			mapper.Add(ref.gvk, meta.RESTScopeNamespace)
+		}
+		for gvk := range scheme.Scheme.AllKnownTypes() {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+		return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(mapper).WithObjects(objs...).Build()
+	}
+
+	unused := func(reader client.Reader, secrets ...*corev1.Secret) []string {
+		refs := NewSecretReferences(reader)
+		out := []string{}
+		for _, s := range secrets {
+			for _, v := range CheckSecretAgainstPolicy(s, policy, WithAccessAnalysis(), WithReferences(refs)) {
+				out = append(out, s.Name+": "+v.RuleID)
+			}
+		}
+		return out
+	}
+
+	It("Should report Secrets unreferenced for longer than afterDays", func() {
+		Expect(unused(newReader(),
+			secret("old", 8*24*time.Hour),
+			secret("recent", 24*time.Hour),
+			secret("untracked", 0),
+		)).To(ConsistOf("old: unused"))
+	})
+
+	It("Should find references of Pods, workloads, ServiceAccounts and Ingresses", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "apps"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:    "app",
+				Image:   "app",
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "pod-env"}}}},
+			}}},
+		}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "apps"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "app"}},
+				Volumes: []corev1.Volume{{
+					Name:         "config",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "scaled-down"}},
+				}},
+			}}},
+		}
+		sa := &corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "apps"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "pull"}},
+		}
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+			Spec:       networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{SecretName: "web-tls"}}},
+		}
+		token := secret("builder-token", 30*24*time.Hour)
+		token.Type = corev1.SecretTypeServiceAccountToken
+		token.Annotations[corev1.ServiceAccountNameKey] = "builder"
+		orphanToken := secret("removed-token", 30*24*time.Hour)
+		orphanToken.Type = corev1.SecretTypeServiceAccountToken
+		orphanToken.Annotations[corev1.ServiceAccountNameKey] = "removed"
+
+		old := 30 * 24 * time.Hour
+		Expect(unused(newReader(pod, deployment, sa, ingress),
+			secret("pod-env", old), secret("scaled-down", old), secret("pull", old), secret("web-tls", old),
+			token, orphanToken, secret("orphan", old),
+		)).To(ConsistOf("removed-token: unused", "orphan: unused"))
+	})
+
+	It("Should find references of known custom resources", func() {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(customReferences[0].gvk)
+		certificate.SetNamespace("apps")
+		certificate.SetName("web")
+		Expect(unstructured.SetNestedField(certificate.Object, "web-cert", "spec", "secretName")).To(Succeed())
+
+		externalSecret := &unstructured.Unstructured{}
+		externalSecret.SetGroupVersionKind(customReferences[1].gvk)
+		externalSecret.SetNamespace("apps")
+		externalSecret.SetName("db")
+
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(customReferences[3].gvk)
+		gateway.SetNamespace("apps")
+		gateway.SetName("edge")
+		Expect(unstructured.SetNestedSlice(gateway.Object, []any{
+			map[string]any{"name": "https", "tls": map[string]any{"certificateRefs": []any{map[string]any{"name": "edge-tls"}}}},
+		}, "spec", "listeners")).To(Succeed())
+
+		old := 30 * 24 * time.Hour
+		Expect(unused(newReader(certificate, externalSecret, gateway),
+			secret("web-cert", old), secret("db", old), secret("edge-tls", old), secret("orphan", old),
+		)).To(ConsistOf("orphan: unused"))
+	})
+
+	It("Should resolve Gateway certificateRefs in the namespace they name", func() {
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(customReferences[3].gvk)
+		gateway.SetNamespace("ingress")
+		gateway.SetName("shared")
+		Expect(unstructured.SetNestedSlice(gateway.Object, []any{
+			map[string]any{"name": "https", "tls": map[string]any{"certificateRefs": []any{
+				map[string]any{"name": "edge-tls", "namespace": "apps"},
+				map[string]any{"name": "local-tls"},
+			}}},
+		}, "spec", "listeners")).To(Succeed())
+
+		old := 30 * 24 * time.Hour
+		// A Secret of the Gateway's name in the Gateway's namespace is not the referenced one
+		Expect(unused(newReader(gateway), secret("edge-tls", old), secret("local-tls", old))).To(ConsistOf("local-tls: unused"))
+
+		local := secret("edge-tls", old)
+		local.Namespace = "ingress"
+		Expect(unused(newReader(gateway), local)).To(ConsistOf("edge-tls: unused"))
+	})
+
+	It("Should skip custom resources that are not installed", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		Expect(unused(reader, secret("orphan", 30*24*time.Hour))).To(ConsistOf("orphan: unused"))
+	})
+
+	It("Should treat managed, Helm release and ignored Secrets as used", func() {
+		owned := secret("owned", 30*24*time.Hour)
+		owned.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "1", Controller: ptr.To(true),
+		}}
+		release := secret("sh.helm.release.v1.app.v1", 30*24*time.Hour)
+		release.Type = "helm.sh/release.v1"
+		ignored := secret("ignored", 30*24*time.Hour)
+		ignored.Type = "example.com/token"
+		policy.Spec.AllowedTypes = append(policy.Spec.AllowedTypes, "helm.sh/release.v1", "example.com/token")
+		policy.Spec.Unused.IgnoredTypes = []string{"example.com/token"}
+
+		Expect(unused(newReader(), owned, release, ignored)).To(BeEmpty())
+	})
+
+	It("Should report failed lookups instead of passing", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+		found := CheckSecretAgainstPolicy(secret("orphan", 30*24*time.Hour), policy,
+			WithAccessAnalysis(), WithReferences(NewSecretReferences(reader)))
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].Message).To(ContainSubstring("cannot be determined: forbidden"))
+
+		_, stale, err := Unused(context.Background(), secret("orphan", 30*24*time.Hour), policy.Spec.Unused, NewSecretReferences(reader), time.Now())
+		Expect(err).To(MatchError("forbidden"))
+		Expect(stale).To(BeFalse())
+	})
+
+	It("Should list the referrers of a namespace once and page by page", func() {
+		var mu sync.Mutex
+		podLists := 0
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				var lo client.ListOptions
+				lo.ApplyOptions(opts)
+				Expect(lo.Limit).To(BeNumerically(">", 0))
+				if _, ok := list.(*corev1.PodList); ok {
+					mu.Lock()
+					podLists++
+					mu.Unlock()
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+		refs := NewSecretReferences(reader)
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := refs.Referenced(context.Background(), secret("orphan", 0))
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+		Expect(podLists).To(Equal(1))
+	})
+
+	It("Should record and clear when a Secret became unused", func() {
+		s := secret("db", 0)
+		now := time.Now()
+		Expect(TrackUsage(s, false, now)).To(BeTrue())
+		since, ok := UnusedSince(s)
+		Expect(ok).To(BeTrue())
+		Expect(since).To(BeTemporally("~", now, time.Second))
+		Expect(TrackUsage(s, false, now.Add(time.Hour))).To(BeFalse())
+
+		Expect(TrackUsage(s, true, now)).To(BeTrue())
+		Expect(s.Annotations).NotTo(HaveKey(UnusedSinceAnnotation))
+	})
+
+	It("Should only detect unused Secrets when enabled", func() {
+		refs := NewSecretReferences(newReader())
+		s := secret("orphan", 30*24*time.Hour)
+		Expect(CheckSecretAgainstPolicy(s, policy, WithReferences(refs))).To(BeEmpty())
+		Expect(ActiveRules(policy)).NotTo(ContainElement(RuleUnused))
+		Expect(ActiveRules(policy, WithAccessAnalysis())).To(ContainElement(RuleUnused))
+	})
+})
@@ -0,0 +1,90 @@
+package policytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Kisor-S/secret-policy-operator/internal/cli"
+)
+
+// Formats lists the supported output formats of test results.
+var Formats = []string{cli.FormatHuman, cli.FormatJSON, cli.FormatJUnit}
+
+// Failed reports whether any case failed.
+func Failed(results []CaseResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+// Write renders test results in the given format.
+func Write(w io.Writer, format string, results []CaseResult) error {
+	switch format {
+	case cli.FormatHuman:
+		return writeHuman(w, results)
+	case cli.FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case cli.FormatJUnit:
+		return cli.WriteJUnit(w, junit(results))
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func writeHuman(w io.Writer, results []CaseResult) error {
+	failed := 0
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+			failed++
+		}
+		line := fmt.Sprintf("%s  %s: %s", status, r.File, r.Case)
+		if r.Message != "" {
+			line += ": " + r.Message
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d cases, %d passed, %d failed\n", len(results), len(results)-failed, failed)
+	return err
+}
+
+// junit maps each test file to a test suite.
+func junit(results []CaseResult) cli.JUnitTestSuites {
+	out := cli.JUnitTestSuites{Name: "secretpolicy-test"}
+	index := map[string]int{}
+
+	for _, r := range results {
+		i, ok := index[r.File]
+		if !ok {
+			i = len(out.Suites)
+			index[r.File] = i
+			name := r.Test
+			if name == "" {
+				name = r.File
+			}
+			out.Suites = append(out.Suites, cli.JUnitTestSuite{Name: name})
+		}
+
+		tc := cli.JUnitTestCase{Name: r.Case, Classname: out.Suites[i].Name, File: r.File}
+		if !r.Passed {
+			tc.Failure = &cli.JUnitFailure{Message: r.Message, Type: "assertion"}
+			out.Suites[i].Failures++
+			out.Failures++
+		}
+		out.Suites[i].Cases = append(out.Suites[i].Cases, tc)
+		out.Suites[i].Tests++
+		out.Tests++
+	}
+	return out
+}
@@ -0,0 +1,287 @@
+// Package policytest runs declarative SecretPolicyTest files: a policy, a set
+// of input Secrets and the rule IDs each Secret is expected to violate. Tests
+// run offline through the same engine as the admission webhook, from
+// secretpolicy-cli or from Go tests with RunFiles.
+package policytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/cli"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// Kind is the kind of a test file. Test files are read by tooling only and
+// are never applied to a cluster.
+const Kind = "SecretPolicyTest"
+
+// SecretPolicyTest is a set of test cases for one policy.
+type SecretPolicyTest struct {
+	metav1.TypeMeta `json:",inline"`
+	Metadata        metav1.ObjectMeta `json:"metadata"`
+
+	// Policy is the manifest file holding the policy under test, relative to
+	// the test file.
+	Policy string `json:"policy"`
+
+	// PolicyName selects the policy when the file holds more than one.
+	// +optional
+	PolicyName string `json:"policyName,omitempty"`
+
+	// Resources are further manifest files, relative to the test file, with
+	// the SecretPolicyExceptions and Namespaces the cases depend on.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// Cases are evaluated independently of each other.
+	Cases []TestCase `json:"cases"`
+}
+
+// TestCase is one input Secret and its expected outcome.
+type TestCase struct {
+	Name string `json:"name"`
+
+	// Secret is the input Secret manifest. A missing namespace defaults to
+	// the namespace of a SecretPolicy, or "default".
+	Secret json.RawMessage `json:"secret"`
+
+	Expect Expectation `json:"expect,omitempty"`
+}
+
+// Expectation lists the rule IDs a Secret is expected to violate. Rule IDs
+// are compared as sets, so a rule violated by several keys is listed once.
+// A case without expectations expects the Secret to pass.
+type Expectation struct {
+	// Violations are the rule IDs violated and not waived.
+	// +optional
+	Violations []string `json:"violations,omitempty"`
+
+	// Waived are the rule IDs violated but waived by an exception.
+	// +optional
+	Waived []string `json:"waived,omitempty"`
+
+	// Skip expects the policy not to select the Secret at all.
+	// +optional
+	Skip bool `json:"skip,omitempty"`
+}
+
+// CaseResult is the outcome of one test case.
+type CaseResult struct {
+	File   string `json:"file"`
+	Test   string `json:"test"`
+	Case   string `json:"case"`
+	Passed bool   `json:"passed"`
+	// Message explains a failure.
+	Message string `json:"message,omitempty"`
+}
+
+// Load reads a test file and the manifests it refers to.
+func Load(path string) (*SecretPolicyTest, *cli.Manifests, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	test := &SecretPolicyTest{}
+	if err := yaml.UnmarshalStrict(data, test); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if test.Kind != Kind {
+		return nil, nil, fmt.Errorf("%s: kind must be %s, got %q", path, Kind, test.Kind)
+	}
+	if test.Policy == "" {
+		return nil, nil, fmt.Errorf("%s: policy is required", path)
+	}
+
+	dir := filepath.Dir(path)
+	manifests := &cli.Manifests{DefaultNamespace: "default"}
+	for _, file := range append([]string{test.Policy}, test.Resources...) {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		if err := manifests.LoadPath(file, nil); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return test, manifests, nil
+}
+
+// Run evaluates every case of a test file.
+func Run(path string, now time.Time) ([]CaseResult, error) {
+	test, manifests, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := selectPolicy(test, manifests)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	results := make([]CaseResult, 0, len(test.Cases))
+	for i, tc := range test.Cases {
+		name := tc.Name
+		if name == "" {
+			name = fmt.Sprintf("case %d", i+1)
+		}
+		result := CaseResult{File: path, Test: test.Metadata.Name, Case: name}
+
+		if msg, err := runCase(policy, manifests, tc, now); err != nil {
+			result.Message = err.Error()
+		} else {
+			result.Passed = msg == ""
+			result.Message = msg
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func selectPolicy(test *SecretPolicyTest, m *cli.Manifests) (compliancev1alpha1.PolicyObject, error) {
+	var found []compliancev1alpha1.PolicyObject
+	for _, p := range m.Policies {
+		if test.PolicyName == "" || p.GetName() == test.PolicyName {
+			found = append(found, p)
+		}
+	}
+
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) == 0 && test.PolicyName != "":
+		return nil, fmt.Errorf("policy %q not found in %s", test.PolicyName, test.Policy)
+	case len(found) == 0:
+		return nil, fmt.Errorf("no policy found in %s", test.Policy)
+	default:
+		return nil, fmt.Errorf("%s holds %d policies, set policyName", test.Policy, len(found))
+	}
+}
+
+// runCase returns a failure message, or "" when the case passes. Errors mean
+// the case itself is invalid.
+func runCase(
+	policy compliancev1alpha1.PolicyObject,
+	shared *cli.Manifests,
+	tc TestCase,
+	now time.Time,
+) (string, error) {
+	if len(tc.Secret) == 0 {
+		return "", fmt.Errorf("secret is required")
+	}
+
+	defaultNamespace := policy.GetNamespace()
+	if defaultNamespace == "" {
+		defaultNamespace = shared.DefaultNamespace
+	}
+	input := &cli.Manifests{DefaultNamespace: defaultNamespace}
+	if err := input.Load(bytes.NewReader(tc.Secret), "secret"); err != nil {
+		return "", err
+	}
+	if len(input.Secrets) != 1 || len(input.Policies) > 0 {
+		return "", fmt.Errorf("secret must be a single Secret manifest")
+	}
+
+	secret := input.Secrets[0].Secret
+	internalpolicy.EnsureRotationRecord(secret, now)
+
+	inScope, err := internalpolicy.SecretInScope(secret, shared.NamespaceLabelsFor(secret.Namespace), policy)
+	if err != nil {
+		return "", err
+	}
+	if !inScope {
+		if tc.Expect.Skip {
+			return "", nil
+		}
+		return "policy does not select the Secret", nil
+	}
+	if tc.Expect.Skip {
+		return "expected the policy not to select the Secret", nil
+	}
+
+	found := internalpolicy.CheckSecretAgainstPolicy(secret, policy)
+	found, waived := internalpolicy.ApplyExceptions(found, secret, policy, shared.Exceptions, now)
+
+	var violated, waivedRules []string
+	for _, v := range found {
+		violated = append(violated, v.RuleID)
+	}
+	for _, w := range waived {
+		waivedRules = append(waivedRules, w.RuleID)
+	}
+
+	if msg := compare("violations", tc.Expect.Violations, violated); msg != "" {
+		return msg, nil
+	}
+	return compare("waived", tc.Expect.Waived, waivedRules), nil
+}
+
+// compare reports the difference between expected and actual rule IDs.
+func compare(what string, expected, actual []string) string {
+	want, got := uniqueSorted(expected), uniqueSorted(actual)
+	if slices.Equal(want, got) {
+		return ""
+	}
+	return fmt.Sprintf("expected %s %v, got %v", what, want, got)
+}
+
+func uniqueSorted(list []string) []string {
+	out := slices.Clone(list)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Find expands directories into the SecretPolicyTest files they contain.
+// Files named explicitly are always returned.
+func Find(paths ...string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if path == root {
+				files = append(files, path)
+				return nil
+			}
+			if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+				return nil
+			}
+
+			ok, err := isTestFile(path)
+			if ok {
+				files = append(files, path)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func isTestFile(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var meta metav1.TypeMeta
+	// Other manifests, including multi-document files, are not tests
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return false, nil
+	}
+	return meta.Kind == Kind, nil
+}
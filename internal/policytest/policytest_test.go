@@ -0,0 +1,229 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policytest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestSamplePolicies keeps the sample policies and their tests in sync.
+func TestSamplePolicies(t *testing.T) {
+	RunFiles(t, "../../config/samples/tests/*.yaml")
+}
+
+const policies = `
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicy
+metadata:
+  name: db
+  namespace: apps
+spec:
+  allowedTypes: ["Opaque"]
+  disallowedKeys: ["password", "token"]
+  secretSelector:
+    matchLabels:
+      app: db
+---
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicy
+metadata:
+  name: other
+  namespace: apps
+spec:
+  allowedTypes: []
+`
+
+const exception = `
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyException
+metadata:
+  name: legacy
+  namespace: apps
+spec:
+  policyRef:
+    name: db
+  rules: ["disallowedKeys"]
+  secretSelector:
+    matchLabels:
+      legacy: "true"
+  expiresAt: "2999-01-01T00:00:00Z"
+  justification: migrating
+`
+
+var _ = Describe("Run", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "policies.yaml"), []byte(policies), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "exception.yaml"), []byte(exception), 0o600)).To(Succeed())
+	})
+
+	write := func(test string) string {
+		path := filepath.Join(dir, "tests", "db.yaml")
+		Expect(os.MkdirAll(filepath.Dir(path), 0o700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(test), 0o600)).To(Succeed())
+		return path
+	}
+
+	run := func(test string) []CaseResult {
+		results, err := Run(write(test), time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		return results
+	}
+
+	It("Should compare violated and waived rule IDs per case", func() {
+		results := run(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyTest
+metadata:
+  name: db
+policy: ../policies.yaml
+policyName: db
+resources: [../exception.yaml]
+cases:
+- name: both keys violate the same rule
+  secret:
+    apiVersion: v1
+    kind: Secret
+    metadata: {name: db, labels: {app: db}}
+    stringData: {password: a, token: b}
+  expect:
+    violations: [disallowedKeys]
+- name: wrong expectation
+  secret:
+    apiVersion: v1
+    kind: Secret
+    metadata: {name: db, labels: {app: db}}
+    type: kubernetes.io/tls
+  expect:
+    violations: [disallowedKeys]
+- name: waived by the exception
+  secret:
+    apiVersion: v1
+    kind: Secret
+    metadata: {name: db, labels: {app: db, legacy: "true"}}
+    stringData: {password: a}
+  expect:
+    waived: [disallowedKeys]
+- name: not selected
+  secret:
+    apiVersion: v1
+    kind: Secret
+    metadata: {name: web, labels: {app: web}}
+  expect:
+    skip: true
+- secret:
+    apiVersion: v1
+    kind: Secret
+    metadata: {name: web, labels: {app: web}}
+`)
+		Expect(results).To(HaveLen(5))
+		Expect(results[0]).To(And(HaveField("Passed", true), HaveField("Test", "db")))
+		Expect(results[1].Passed).To(BeFalse())
+		Expect(results[1].Message).To(Equal("expected violations [disallowedKeys], got [allowedTypes]"))
+		Expect(results[2].Passed).To(BeTrue())
+		Expect(results[3].Passed).To(BeTrue())
+		Expect(results[4]).To(And(
+			HaveField("Case", "case 5"),
+			HaveField("Passed", false),
+			HaveField("Message", "policy does not select the Secret"),
+		))
+		Expect(Failed(results)).To(BeTrue())
+	})
+
+	It("Should fail cases whose input is not a single Secret", func() {
+		results := run(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyTest
+policy: ../policies.yaml
+policyName: db
+cases:
+- name: missing secret
+- name: not a secret
+  secret: {apiVersion: v1, kind: ConfigMap, metadata: {name: cm}}
+`)
+		Expect(results[0].Message).To(Equal("secret is required"))
+		Expect(results[1].Message).To(Equal("secret must be a single Secret manifest"))
+	})
+
+	It("Should reject ambiguous and invalid test files", func() {
+		_, err := Run(write(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyTest
+policy: ../policies.yaml
+cases: []
+`), time.Now())
+		Expect(err).To(MatchError(ContainSubstring("holds 2 policies, set policyName")))
+
+		_, err = Run(write(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicyTest
+policy: ../policies.yaml
+case: []
+`), time.Now())
+		Expect(err).To(MatchError(ContainSubstring(`unknown field "case"`)))
+
+		_, err = Run(write(`
+apiVersion: compliance.security.local/v1alpha1
+kind: SecretPolicy
+policy: ../policies.yaml
+`), time.Now())
+		Expect(err).To(MatchError(ContainSubstring("kind must be SecretPolicyTest")))
+	})
+
+	It("Should only find test files when searching directories", func() {
+		path := write("apiVersion: compliance.security.local/v1alpha1\nkind: SecretPolicyTest\npolicy: ../policies.yaml\n")
+
+		files, err := Find(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(ConsistOf(path))
+
+		files, err = Find(filepath.Join(dir, "policies.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Write", func() {
+	results := []CaseResult{
+		{File: "db.yaml", Test: "db", Case: "passes", Passed: true},
+		{File: "db.yaml", Test: "db", Case: "fails", Message: "expected violations [a], got []"},
+	}
+
+	It("Should summarize human output", func() {
+		var buf bytes.Buffer
+		Expect(Write(&buf, "human", results)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("FAIL  db.yaml: fails: expected violations [a], got []"))
+		Expect(buf.String()).To(ContainSubstring("2 cases, 1 passed, 1 failed"))
+	})
+
+	It("Should write one JUnit suite per test file", func() {
+		suites := junit(results)
+		Expect(suites.Tests).To(Equal(2))
+		Expect(suites.Failures).To(Equal(1))
+		Expect(suites.Suites).To(HaveLen(1))
+		Expect(suites.Suites[0].Cases[1].Failure.Message).To(Equal("expected violations [a], got []"))
+	})
+})
@@ -0,0 +1,46 @@
+package policytest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// RunFiles runs the SecretPolicyTest files matching the glob patterns, each
+// case as a subtest of t, e.g.
+//
+//	func TestPolicies(t *testing.T) {
+//		policytest.RunFiles(t, "policies/tests/*.yaml")
+//	}
+func RunFiles(t *testing.T, patterns ...string) {
+	t.Helper()
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("invalid pattern %q: %v", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no SecretPolicyTest files match %v", patterns)
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			results, err := Run(file, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range results {
+				t.Run(r.Case, func(t *testing.T) {
+					if !r.Passed {
+						t.Error(r.Message)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package tracing configures OpenTelemetry tracing for the operator. Spans
+// are always created through the global tracer provider; until Setup installs
+// an exporting provider that is the no-op provider, so instrumented code
+// paths cost next to nothing when tracing is off.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Kisor-S/secret-policy-operator"
+
+// DefaultServiceName is reported as service.name unless OTEL_SERVICE_NAME
+// or Options.ServiceName override it.
+const DefaultServiceName = "secret-policy-operator"
+
+// Attribute keys set on the operator's spans.
+const (
+	KeyPolicy     = attribute.Key("secretpolicy.policy")
+	KeySecret     = attribute.Key("secretpolicy.secret")
+	KeyRule       = attribute.Key("secretpolicy.rule")
+	KeyViolations = attribute.Key("secretpolicy.violations")
+	KeySecrets    = attribute.Key("secretpolicy.secrets")
+	KeyOperation  = attribute.Key("secretpolicy.operation")
+	KeyDecision   = attribute.Key("secretpolicy.decision")
+	KeyWorkload   = attribute.Key("secretpolicy.workload")
+)
+
+// Options configures the OTLP exporter.
+type Options struct {
+	// Endpoint is the host:port of an OTLP/gRPC collector. Tracing is
+	// disabled when it is empty.
+	Endpoint string
+	// Insecure disables TLS towards the collector.
+	Insecure bool
+	// SampleRatio is the fraction of new traces that are sampled. Spans with
+	// a sampled parent are always recorded.
+	SampleRatio float64
+	// ServiceName overrides DefaultServiceName.
+	ServiceName string
+}
+
+// Setup installs a global tracer provider exporting to opts.Endpoint and
+// returns a function that flushes and stops it. When no endpoint is set
+// nothing is installed and the returned function is a no-op.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, errors.New("trace sample ratio must be between 0 and 1")
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	// Later detectors win, so OTEL_SERVICE_NAME still takes precedence
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the operator's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
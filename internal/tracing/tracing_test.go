@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// collector is an in-process OTLP/gRPC trace collector.
+type collector struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	mu       sync.Mutex
+	services []string
+	spans    []*tracepb.Span
+}
+
+func (c *collector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, attr := range rs.GetResource().GetAttributes() {
+			if attr.Key == "service.name" {
+				c.services = append(c.services, attr.GetValue().GetStringValue())
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (c *collector) span(name string) *tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Tracing", func() {
+	ctx := context.Background()
+
+	It("Should keep the no-op provider when no endpoint is set", func() {
+		shutdown, err := Setup(ctx, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(ctx)).To(Succeed())
+
+		_, span := Start(ctx, "noop")
+		defer span.End()
+		Expect(span.IsRecording()).To(BeFalse())
+	})
+
+	It("Should reject an invalid sample ratio", func() {
+		_, err := Setup(ctx, Options{Endpoint: "localhost:4317", SampleRatio: 2})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should export spans to an OTLP collector", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		c := &collector{}
+		server := grpc.NewServer()
+		coltracepb.RegisterTraceServiceServer(server, c)
+		go func() { _ = server.Serve(lis) }()
+		defer server.Stop()
+
+		previous := otel.GetTracerProvider()
+		defer otel.SetTracerProvider(previous)
+
+		shutdown, err := Setup(ctx, Options{Endpoint: lis.Addr().String(), Insecure: true, SampleRatio: 1})
+		Expect(err).NotTo(HaveOccurred())
+
+		parentCtx, parent := Start(ctx, "parent", KeyPolicy.String("team-a/db"))
+		_, child := Start(parentCtx, "child")
+		End(child, errors.New("boom"))
+		End(parent, nil)
+
+		// Shutdown flushes the batch to the collector
+		Expect(shutdown(ctx)).To(Succeed())
+
+		exported := c.span("parent")
+		Expect(exported).NotTo(BeNil())
+		Expect(exported.Attributes).To(ContainElement(HaveField("Key", string(KeyPolicy))))
+
+		failed := c.span("child")
+		Expect(failed).NotTo(BeNil())
+		Expect(failed.ParentSpanId).To(Equal(exported.SpanId))
+		Expect(failed.Status.Code).To(Equal(tracepb.Status_STATUS_CODE_ERROR))
+		Expect(c.services).To(ContainElement(DefaultServiceName))
+	})
+})
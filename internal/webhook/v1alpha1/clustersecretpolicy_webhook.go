@@ -0,0 +1,76 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var clustersecretpolicylog = logf.Log.WithName("clustersecretpolicy-resource")
+
+// +kubebuilder:webhook:path=/validate-compliance-security-local-v1alpha1-clustersecretpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=compliance.security.local,resources=clustersecretpolicies,verbs=create;update,versions=v1alpha1,name=vclustersecretpolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterSecretPolicyValidator validates ClusterSecretPolicy objects with the
+// same rules as SecretPolicyValidator.
+type ClusterSecretPolicyValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterSecretPolicyValidator{}
+
+// SetupClusterSecretPolicyWebhookWithManager registers the webhook for ClusterSecretPolicy in the manager.
+func SetupClusterSecretPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&compliancev1alpha1.ClusterSecretPolicy{}).
+		WithValidator(&ClusterSecretPolicyValidator{}).
+		Complete()
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSecretPolicy.
+func (v *ClusterSecretPolicyValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	policy, ok := obj.(*compliancev1alpha1.ClusterSecretPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSecretPolicy object but got %T", obj)
+	}
+	clustersecretpolicylog.Info("Validation for ClusterSecretPolicy upon creation", "name", policy.GetName())
+
+	return validateSecretPolicy(policy)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterSecretPolicy.
+func (v *ClusterSecretPolicyValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	policy, ok := newObj.(*compliancev1alpha1.ClusterSecretPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterSecretPolicy object for the newObj but got %T", newObj)
+	}
+	clustersecretpolicylog.Info("Validation for ClusterSecretPolicy upon update", "name", policy.GetName())
+
+	return validateSecretPolicy(policy)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterSecretPolicy.
+func (v *ClusterSecretPolicyValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// -----------------------------------------------------------------------------
+// SecretMutator – mutating admission webhook for corev1.Secrets
+// -----------------------------------------------------------------------------
+
+// +kubebuilder:webhook:path=/mutate-v1-secret,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=secrets,verbs=create;update,versions=v1,name=secret.mutator.kishore.dev,admissionReviewVersions=v1
+
+// SecretMutator applies the remediation block of every policy that selects a
+// Secret before the Secret is validated by SecretValidator. It also maintains
+// the operator-owned rotation record for policies with rotation enabled.
+type SecretMutator struct {
+	Client  client.Client
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &SecretMutator{}
+
+// SetupSecretMutatingWebhookWithManager registers the Secret mutating webhook in the manager.
+func SetupSecretMutatingWebhookWithManager(mgr ctrl.Manager) error {
+	mutator := &SecretMutator{
+		Client:  mgr.GetClient(),
+		Decoder: admission.NewDecoder(mgr.GetScheme()),
+	}
+
+	mgr.GetWebhookServer().Register("/mutate-v1-secret",
+		&admission.Webhook{Handler: mutator})
+	return nil
+}
+
+// Handle remediates the Secret and records the applied changes in the
+// mutations annotation, returning them as a JSON patch.
+func (m *SecretMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	secret := &corev1.Secret{}
+	if err := m.Decoder.Decode(req, secret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if isSystemNamespace(secret.Namespace) {
+		return admission.Allowed("skipping remediation for system namespace")
+	}
+
+	var old *corev1.Secret
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		old = &corev1.Secret{}
+		if err := m.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	policies, err := selectingPolicies(ctx, m.Client, secret)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	now := time.Now()
+	var mutations []string
+	trackRotation := false
+	for _, p := range policies {
+		// Rotation is tracked for audit-mode policies too so that the
+		// controller reports accurate ages
+		if p.GetSpec().Rotation.Enabled {
+			trackRotation = true
+		}
+		if !actsAtAdmission(p) {
+			continue
+		}
+
+		ref := internalpolicy.PolicyRef(p)
+		for _, change := range internalpolicy.Remediate(secret, old, p, now) {
+			mutations = append(mutations, fmt.Sprintf("%s: %s", ref, change))
+		}
+	}
+
+	// The rotation record is bookkeeping rather than remediation, so it is
+	// neither listed in the mutations annotation nor returned as a warning
+	tracked := trackRotation && internalpolicy.TrackRotation(secret, old, now)
+
+	if len(mutations) == 0 && !tracked {
+		return admission.Allowed("no remediation needed")
+	}
+
+	if len(mutations) > 0 {
+		record, err := json.Marshal(mutations)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[internalpolicy.MutationsAnnotation] = string(record)
+	}
+
+	marshaled, err := json.Marshal(secret)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	resp := admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+	return resp.WithWarnings(mutations...)
+}
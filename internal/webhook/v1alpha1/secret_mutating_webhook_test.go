@@ -0,0 +1,181 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	jsonpatch "gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+var _ = Describe("Secret Mutating Webhook", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	handle := func(req admission.Request, objs ...client.Object) admission.Response {
+		mutator := &SecretMutator{
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			Decoder: admission.NewDecoder(scheme.Scheme),
+		}
+		return mutator.Handle(ctx, req)
+	}
+
+	patchPaths := func(patches []jsonpatch.JsonPatchOperation) []string {
+		paths := []string{}
+		for _, p := range patches {
+			paths = append(paths, p.Operation+" "+p.Path)
+		}
+		return paths
+	}
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("hunter2")},
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys: []string{"password"},
+				Remediation: &compliancev1alpha1.RemediationSpec{
+					Labels:              map[string]string{"owner": "platform"},
+					StripDisallowedKeys: true,
+				},
+			},
+		}
+	})
+
+	It("Should patch the Secret and record every mutation", func() {
+		resp := handle(newSecretRequest(secret), policy)
+
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(patchPaths(resp.Patches)).To(ConsistOf(
+			"add /metadata/labels",
+			"add /metadata/annotations",
+			"remove /data/password",
+		))
+		Expect(resp.Warnings).To(ConsistOf(
+			"SecretPolicy apps/baseline: added label owner=platform",
+			"SecretPolicy apps/baseline: removed disallowed key password",
+		))
+
+		for _, p := range resp.Patches {
+			if p.Path != "/metadata/annotations" {
+				continue
+			}
+			record := p.Value.(map[string]any)[internalpolicy.MutationsAnnotation].(string)
+			var mutations []string
+			Expect(json.Unmarshal([]byte(record), &mutations)).To(Succeed())
+			Expect(mutations).To(Equal([]string(resp.Warnings)))
+		}
+	})
+
+	It("Should not patch a compliant Secret", func() {
+		secret.Labels = map[string]string{"owner": "team-a"}
+		delete(secret.Data, "password")
+
+		resp := handle(newSecretRequest(secret), policy)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("Should not mutate for audit-mode policies or system namespaces", func() {
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionAudit
+		Expect(handle(newSecretRequest(secret), policy).Patches).To(BeEmpty())
+
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionEnforce
+		policy.Namespace = "kube-system"
+		secret.Namespace = "kube-system"
+		Expect(handle(newSecretRequest(secret), policy).Patches).To(BeEmpty())
+	})
+
+	It("Should stamp lastRotated only when data changes on update", func() {
+		policy.Spec.Remediation = &compliancev1alpha1.RemediationSpec{StampLastRotated: true}
+		old := secret.DeepCopy()
+
+		update := func() admission.Request {
+			req := newSecretRequest(secret)
+			raw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			req.Operation = admissionv1.Update
+			req.OldObject = runtime.RawExtension{Raw: raw}
+			return req
+		}
+
+		Expect(handle(update(), policy).Patches).To(BeEmpty())
+
+		secret.Data["password"] = []byte("rotated")
+		Expect(patchPaths(handle(update(), policy).Patches)).To(ConsistOf("add /metadata/annotations"))
+	})
+
+	It("Should record the data hash and rotation time for policies with rotation enabled", func() {
+		policy.Spec.Remediation = nil
+		policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionAudit
+		policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30}
+
+		resp := handle(newSecretRequest(secret), policy)
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(BeEmpty())
+		Expect(resp.Patches).To(HaveLen(1))
+
+		annotations := resp.Patches[0].Value.(map[string]any)
+		Expect(annotations).To(HaveKeyWithValue(internalpolicy.DataHashAnnotation, internalpolicy.DataHash(secret.Data)))
+		Expect(annotations).To(HaveKey(internalpolicy.RotatedAtAnnotation))
+		Expect(annotations).NotTo(HaveKey(internalpolicy.MutationsAnnotation))
+	})
+
+	It("Should revert hand edits to the rotation record when data is unchanged", func() {
+		policy.Spec.Remediation = nil
+		policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30}
+
+		old := secret.DeepCopy()
+		old.Annotations = map[string]string{
+			internalpolicy.DataHashAnnotation:  internalpolicy.DataHash(old.Data),
+			internalpolicy.RotatedAtAnnotation: "2025-01-01T00:00:00Z",
+		}
+		secret.Annotations = map[string]string{
+			internalpolicy.DataHashAnnotation:  internalpolicy.DataHash(secret.Data),
+			internalpolicy.RotatedAtAnnotation: "2099-01-01T00:00:00Z",
+		}
+
+		req := newSecretRequest(secret)
+		raw, err := json.Marshal(old)
+		Expect(err).NotTo(HaveOccurred())
+		req.Operation = admissionv1.Update
+		req.OldObject = runtime.RawExtension{Raw: raw}
+
+		resp := handle(req, policy)
+		Expect(resp.Patches).To(ConsistOf(HaveField("Value", "2025-01-01T00:00:00Z")))
+	})
+})
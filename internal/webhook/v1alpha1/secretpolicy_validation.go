@@ -0,0 +1,512 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// builtinSecretTypes maps every Secret type known to Kubernetes to the data
+// keys a Secret of that type must contain.
+var builtinSecretTypes = map[corev1.SecretType][]string{
+	corev1.SecretTypeOpaque:              nil,
+	corev1.SecretTypeServiceAccountToken: nil,
+	corev1.SecretTypeDockercfg:           {corev1.DockerConfigKey},
+	corev1.SecretTypeDockerConfigJson:    {corev1.DockerConfigJsonKey},
+	corev1.SecretTypeBasicAuth:           nil,
+	corev1.SecretTypeSSHAuth:             {corev1.SSHAuthPrivateKey},
+	corev1.SecretTypeTLS:                 {corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+	corev1.SecretTypeBootstrapToken:      nil,
+}
+
+var supportedAlertMethods = []string{
+	compliancev1alpha1.AlertMethodEmail,
+	compliancev1alpha1.AlertMethodSlack,
+	compliancev1alpha1.AlertMethodWebhook,
+}
+
+// validateSecretPolicy runs the spec validation shared by SecretPolicy and
+// ClusterSecretPolicy. Problems that would make the policy misbehave are
+// returned as an Invalid API error; risky but legal settings become warnings.
+func validateSecretPolicy(policy compliancev1alpha1.PolicyObject) (admission.Warnings, error) {
+	spec := policy.GetSpec()
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateSelector(specPath.Child("namespaceSelector"), spec.NamespaceSelector)...)
+	allErrs = append(allErrs, validateSelector(specPath.Child("secretSelector"), spec.SecretSelector)...)
+	allErrs = append(allErrs, validateAllowedTypes(specPath.Child("allowedTypes"), spec.AllowedTypes)...)
+	allErrs = append(allErrs, validateDisallowedKeys(specPath.Child("disallowedKeys"), spec.DisallowedKeys, spec.AllowedTypes)...)
+	allErrs = append(allErrs, validateKeyPatterns(specPath.Child("allowedKeyPatterns"), spec.AllowedKeyPatterns, spec.AllowedTypes, true)...)
+	allErrs = append(allErrs, validateKeyPatterns(specPath.Child("disallowedKeyPatterns"), spec.DisallowedKeyPatterns, spec.AllowedTypes, false)...)
+	allErrs = append(allErrs, validateRules(specPath.Child("rules"), spec.Rules)...)
+	allErrs = append(allErrs, validateRotation(specPath.Child("rotation"), spec.Rotation)...)
+	allErrs = append(allErrs, validateAlerting(specPath.Child("alerting"), spec.Alerting, policy.GetNamespace())...)
+	allErrs = append(allErrs, validateUnique(specPath.Child("accessRules", "allowedNamespaces"), spec.AccessRules.AllowedNamespaces)...)
+	allErrs = append(allErrs, validateServiceAccounts(specPath.Child("accessRules", "allowedServiceAccounts"), spec.AccessRules.AllowedServiceAccounts)...)
+	allErrs = append(allErrs, validateRemediation(specPath.Child("remediation"), spec.Remediation)...)
+	allErrs = append(allErrs, validateContentScan(specPath.Child("contentScan"), spec.ContentScan)...)
+	allErrs = append(allErrs, validateTLS(specPath.Child("tls"), spec.TLS)...)
+	allErrs = append(allErrs, validatePayload(specPath.Child("payload"), spec.Payload)...)
+	allErrs = append(allErrs, validateUnused(specPath.Child("unused"), spec.Unused)...)
+
+	warnings := secretPolicyWarnings(policy)
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(policyGroupKind(policy), policy.GetName(), allErrs)
+}
+
+func validateSelector(fldPath *field.Path, selector *metav1.LabelSelector) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+	return metav1validation.ValidateLabelSelector(selector, metav1validation.LabelSelectorValidationOptions{}, fldPath)
+}
+
+// validateAllowedTypes rejects an empty list, which would deny every Secret,
+// as well as duplicates and strings that cannot be a Secret type.
+func validateAllowedTypes(fldPath *field.Path, types []string) field.ErrorList {
+	if len(types) == 0 {
+		return field.ErrorList{field.Required(fldPath, "at least one Secret type must be allowed; an empty list rejects every Secret")}
+	}
+
+	allErrs := validateUnique(fldPath, types)
+	for i, t := range types {
+		if msg := invalidSecretType(t); msg != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), t, msg))
+		}
+	}
+	return allErrs
+}
+
+// invalidSecretType explains why t is not a usable Secret type, or returns "".
+// Built-in types must match exactly; custom types must be domain-prefixed so
+// that typos such as "opaque" or "kubernetes.io/tsl" are caught.
+func invalidSecretType(t string) string {
+	if _, ok := builtinSecretTypes[corev1.SecretType(t)]; ok {
+		return ""
+	}
+	for builtin := range builtinSecretTypes {
+		if strings.EqualFold(t, string(builtin)) {
+			return fmt.Sprintf("Secret types are case-sensitive, did you mean %q?", builtin)
+		}
+	}
+
+	prefix, _, found := strings.Cut(t, "/")
+	if !found {
+		return "must be a built-in Secret type or a domain-prefixed custom type such as example.com/my-type"
+	}
+	if prefix == "kubernetes.io" {
+		return "unknown built-in Secret type"
+	}
+	if errs := validation.IsQualifiedName(t); len(errs) > 0 {
+		return strings.Join(errs, "; ")
+	}
+	return ""
+}
+
+// validateDisallowedKeys rejects duplicate or malformed keys and keys that
+// every Secret of an allowed type is required to contain.
+func validateDisallowedKeys(fldPath *field.Path, keys, allowedTypes []string) field.ErrorList {
+	allErrs := validateUnique(fldPath, keys)
+	for i, key := range keys {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, strings.Join(errs, "; ")))
+			continue
+		}
+		for _, t := range allowedTypes {
+			for _, required := range builtinSecretTypes[corev1.SecretType(t)] {
+				if key == required {
+					allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key,
+						fmt.Sprintf("key is required by allowed type %s, so every such Secret would be rejected", t)))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateKeyPatterns rejects duplicate patterns, patterns that do not
+// compile and, like validateDisallowedKeys, patterns that reject a key every
+// Secret of an allowed type is required to contain. Allowed patterns are
+// checked as a whole: a required key must match at least one of them.
+func validateKeyPatterns(fldPath *field.Path, patterns, allowedTypes []string, allowed bool) field.ErrorList {
+	allErrs := validateUnique(fldPath, patterns)
+	var res []*regexp.Regexp
+	for i, pattern := range patterns {
+		re, err := internalpolicy.CompileKeyPattern(pattern)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), pattern, err.Error()))
+			continue
+		}
+		res = append(res, re)
+
+		if allowed {
+			continue
+		}
+		for _, t := range allowedTypes {
+			for _, required := range builtinSecretTypes[corev1.SecretType(t)] {
+				if re.MatchString(required) {
+					allErrs = append(allErrs, field.Invalid(fldPath.Index(i), pattern,
+						fmt.Sprintf("pattern matches key %s, which is required by allowed type %s, so every such Secret would be rejected", required, t)))
+				}
+			}
+		}
+	}
+	if !allowed || len(res) < len(patterns) || len(patterns) == 0 {
+		return allErrs
+	}
+
+	for _, t := range allowedTypes {
+		for _, required := range builtinSecretTypes[corev1.SecretType(t)] {
+			if !slices.ContainsFunc(res, func(re *regexp.Regexp) bool { return re.MatchString(required) }) {
+				allErrs = append(allErrs, field.Invalid(fldPath, patterns,
+					fmt.Sprintf("no pattern matches key %s, which is required by allowed type %s, so every such Secret would be rejected", required, t)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateRules rejects CEL rules that do not compile.
+func validateRules(fldPath *field.Path, rules []compliancev1alpha1.CELRule) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, rule := range rules {
+		if _, err := internalpolicy.CompileRule(rule); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("expression"), rule.Expression, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateRotation(fldPath *field.Path, rotation compliancev1alpha1.RotationSpec) field.ErrorList {
+	if rotation.IntervalDays < 0 || (rotation.Enabled && rotation.IntervalDays == 0) {
+		return field.ErrorList{field.Invalid(fldPath.Child("intervalDays"), rotation.IntervalDays,
+			"must be greater than 0 when rotation is enabled")}
+	}
+	return nil
+}
+
+// validateAlerting checks the sink configuration. namespace is the namespace
+// of the policy, empty for a ClusterSecretPolicy.
+func validateAlerting(fldPath *field.Path, alerting compliancev1alpha1.AlertingSpec, namespace string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if alerting.Method == "" {
+		if alerting.EnableAlerts {
+			allErrs = append(allErrs, field.Required(fldPath.Child("method"), "required when alerts are enabled"))
+		}
+	} else if !contains(supportedAlertMethods, alerting.Method) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("method"), alerting.Method, supportedAlertMethods))
+	}
+
+	refPath := fldPath.Child("credentialsRef")
+	switch ref := alerting.CredentialsRef; {
+	case ref == nil:
+		if alerting.EnableAlerts {
+			allErrs = append(allErrs, field.Required(refPath, "required when alerts are enabled"))
+		}
+	case ref.Name == "":
+		allErrs = append(allErrs, field.Required(refPath.Child("name"), ""))
+	case namespace == "" && ref.Namespace == "":
+		allErrs = append(allErrs, field.Required(refPath.Child("namespace"), "required for cluster-scoped policies"))
+	case namespace != "" && ref.Namespace != "" && ref.Namespace != namespace:
+		allErrs = append(allErrs, field.Invalid(refPath.Child("namespace"), ref.Namespace,
+			"a SecretPolicy may only reference a Secret in its own namespace"))
+	}
+
+	if alerting.RepeatInterval != nil && alerting.RepeatInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("repeatInterval"), alerting.RepeatInterval.Duration.String(),
+			"must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateRemediation(fldPath *field.Path, remediation *compliancev1alpha1.RemediationSpec) field.ErrorList {
+	if remediation == nil {
+		return nil
+	}
+
+	allErrs := metav1validation.ValidateLabels(remediation.Labels, fldPath.Child("labels"))
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(remediation.Annotations, fldPath.Child("annotations"))...)
+	if t := remediation.DefaultType; t != "" {
+		if msg := invalidSecretType(t); msg != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("defaultType"), t, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateContentScan checks the content scanning settings. The threshold
+// is a Quantity, which the schema cannot bound.
+func validateContentScan(fldPath *field.Path, scan *compliancev1alpha1.ContentScanSpec) field.ErrorList {
+	if scan == nil {
+		return nil
+	}
+
+	allErrs := validateKeys(fldPath.Child("ignoreKeys"), scan.IgnoreKeys)
+	if c := scan.Credentials; c != nil {
+		credPath := fldPath.Child("credentials")
+		patterns := make([]string, len(c.Patterns))
+		for i, p := range c.Patterns {
+			patterns[i] = string(p)
+		}
+		allErrs = append(allErrs, validateUnique(credPath.Child("patterns"), patterns)...)
+		allErrs = append(allErrs, validateUnique(credPath.Child("allowedTypes"), c.AllowedTypes)...)
+		for i, t := range c.AllowedTypes {
+			if msg := invalidSecretType(t); msg != "" {
+				allErrs = append(allErrs, field.Invalid(credPath.Child("allowedTypes").Index(i), t, msg))
+			}
+		}
+	}
+	if e := scan.Entropy; e != nil && e.Threshold != nil {
+		// Entropy is measured per byte, so it never exceeds 8 bits
+		if t := e.Threshold.AsApproximateFloat64(); t <= 0 || t >= 8 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("entropy", "threshold"), e.Threshold.String(),
+				"must be between 0 and 8 bits per character"))
+		}
+	}
+	if w := scan.WeakPasswords; w != nil {
+		allErrs = append(allErrs, validateKeys(fldPath.Child("weakPasswords", "keys"), w.Keys)...)
+	}
+	return allErrs
+}
+
+// validateTLS rejects unknown signature algorithms, SAN patterns that do not
+// compile and CA bundles without certificates.
+func validateTLS(fldPath *field.Path, spec *compliancev1alpha1.TLSSpec) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+
+	algPath := fldPath.Child("forbiddenSignatureAlgorithms")
+	allErrs := validateUnique(algPath, spec.ForbiddenSignatureAlgorithms)
+	for i, alg := range spec.ForbiddenSignatureAlgorithms {
+		if !internalpolicy.IsSignatureAlgorithm(alg) {
+			allErrs = append(allErrs, field.Invalid(algPath.Index(i), alg,
+				"must be a signature algorithm name of Go's crypto/x509, e.g. SHA1-RSA or ECDSA-SHA1"))
+		}
+	}
+	for i, pattern := range spec.RequiredSANs {
+		if _, err := internalpolicy.CompileKeyPattern(pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requiredSANs").Index(i), pattern, err.Error()))
+		}
+	}
+	if spec.CABundle != "" {
+		if _, err := internalpolicy.ParseCertificates([]byte(spec.CABundle)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("caBundle"), "<bundle>", err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validatePayload rejects registries that are not a host, a host:port or a
+// "*." wildcard domain.
+func validatePayload(fldPath *field.Path, spec *compliancev1alpha1.PayloadSpec) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+
+	regPath := fldPath.Child("allowedRegistries")
+	allErrs := validateUnique(regPath, spec.AllowedRegistries)
+	for i, registry := range spec.AllowedRegistries {
+		host, port, hasPort := strings.Cut(strings.TrimPrefix(registry, "*."), ":")
+		errs := validation.IsDNS1123Subdomain(strings.ToLower(host))
+		if hasPort {
+			if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+				errs = append(errs, "port must be a number between 0 and 65535")
+			}
+		}
+		if len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(regPath.Index(i), registry, strings.Join(errs, "; ")))
+		}
+	}
+
+	algorithms := make([]string, len(spec.AllowedSSHKeyAlgorithms))
+	for i, alg := range spec.AllowedSSHKeyAlgorithms {
+		algorithms[i] = string(alg)
+	}
+	allErrs = append(allErrs, validateUnique(fldPath.Child("allowedSSHKeyAlgorithms"), algorithms)...)
+	return allErrs
+}
+
+// validateUnused rejects ignored types that cannot be a Secret type.
+func validateUnused(fldPath *field.Path, spec *compliancev1alpha1.UnusedSpec) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+
+	typesPath := fldPath.Child("ignoredTypes")
+	allErrs := validateUnique(typesPath, spec.IgnoredTypes)
+	for i, t := range spec.IgnoredTypes {
+		if msg := invalidSecretType(t); msg != "" {
+			allErrs = append(allErrs, field.Invalid(typesPath.Index(i), t, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateServiceAccounts accepts "name", "namespace/name" and "namespace/*".
+func validateServiceAccounts(fldPath *field.Path, entries []string) field.ErrorList {
+	allErrs := validateUnique(fldPath, entries)
+	for i, entry := range entries {
+		var errs []string
+		ns, name, found := strings.Cut(entry, "/")
+		if found {
+			errs = append(errs, validation.IsDNS1123Label(ns)...)
+		} else {
+			name = entry
+		}
+		if name != "*" || !found {
+			errs = append(errs, validation.IsDNS1123Subdomain(name)...)
+		}
+		if len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), entry, strings.Join(errs, "; ")))
+		}
+	}
+	return allErrs
+}
+
+// validateKeys rejects duplicate and malformed Secret data keys.
+func validateKeys(fldPath *field.Path, keys []string) field.ErrorList {
+	allErrs := validateUnique(fldPath, keys)
+	for i, key := range keys {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, strings.Join(errs, "; ")))
+		}
+	}
+	return allErrs
+}
+
+func validateUnique(fldPath *field.Path, values []string) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(values))
+	for i, v := range values {
+		if seen[v] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), v))
+		}
+		seen[v] = true
+	}
+	return allErrs
+}
+
+// secretPolicyWarnings flags settings that are legal but probably not what
+// the author intended.
+func secretPolicyWarnings(policy compliancev1alpha1.PolicyObject) admission.Warnings {
+	spec := policy.GetSpec()
+	var warnings admission.Warnings
+
+	if !spec.Rotation.Enabled && spec.Rotation.IntervalDays > 0 {
+		warnings = append(warnings, "spec.rotation.intervalDays is ignored because rotation is not enabled")
+	}
+	if !spec.Alerting.EnableAlerts && spec.Alerting.Method != "" {
+		warnings = append(warnings, "spec.alerting.method is ignored because alerts are not enabled")
+	}
+	if !spec.Encryption.EnforceBase64 && spec.Encryption.Base64Mode != "" {
+		warnings = append(warnings, "spec.encryption.base64Mode is ignored because enforceBase64 is false")
+	}
+	if spec.Encryption.EnforceBase64 && spec.Encryption.Base64Mode == "strict" {
+		warnings = append(warnings, "spec.encryption.base64Mode strict rejects PEM certificates and keys stored as-is; "+
+			"use spec.contentScan to detect credentials")
+	}
+
+	if spec.MaxValueBytes > 0 && spec.MaxTotalBytes > 0 && spec.MaxValueBytes >= spec.MaxTotalBytes {
+		warnings = append(warnings, "spec.maxValueBytes has no effect because it is not below maxTotalBytes")
+	}
+	if spec.MaxTotalBytes > corev1.MaxSecretSize {
+		warnings = append(warnings, fmt.Sprintf("spec.maxTotalBytes has no effect because the API server rejects Secrets above %d bytes", corev1.MaxSecretSize))
+	}
+
+	if t := spec.TLS; t != nil {
+		if !contains(spec.AllowedTypes, string(corev1.SecretTypeTLS)) {
+			warnings = append(warnings, "spec.tls has no effect because kubernetes.io/tls is not in allowedTypes")
+		}
+		if t.CABundle != "" && !t.VerifyChain {
+			warnings = append(warnings, "spec.tls.caBundle is ignored because verifyChain is false")
+		}
+	}
+
+	if len(spec.AccessRules.AllowedServiceAccounts) > 0 && spec.Action() == compliancev1alpha1.EnforcementActionEnforce {
+		warnings = append(warnings, "spec.accessRules.allowedServiceAccounts is reported by scans only; "+
+			"admission never denies a Secret because of who can read it")
+	}
+	if spec.Unused != nil && spec.Action() == compliancev1alpha1.EnforcementActionEnforce {
+		warnings = append(warnings, "spec.unused is reported by scans only; "+
+			"admission never denies a Secret because it is not referenced")
+	}
+
+	if r := spec.Remediation; r != nil {
+		if r.StripDisallowedKeys && len(spec.DisallowedKeys) == 0 {
+			warnings = append(warnings, "spec.remediation.stripDisallowedKeys has no effect because disallowedKeys is empty")
+		}
+		if r.DefaultType != "" && !contains(spec.AllowedTypes, r.DefaultType) {
+			warnings = append(warnings, fmt.Sprintf(
+				"spec.remediation.defaultType %s is not in allowedTypes, so defaulted Secrets still violate the policy", r.DefaultType))
+		}
+	}
+
+	// A namespaced policy only ever sees Secrets in its own namespace
+	if ns := policy.GetNamespace(); ns != "" && len(spec.AccessRules.AllowedNamespaces) > 0 &&
+		!contains(spec.AccessRules.AllowedNamespaces, ns) {
+		warnings = append(warnings, fmt.Sprintf(
+			"spec.accessRules.allowedNamespaces does not include %s, so every Secret selected by this policy violates it", ns))
+	}
+
+	if policy.GetNamespace() == "" && spec.Action() == compliancev1alpha1.EnforcementActionEnforce &&
+		spec.NamespaceSelector == nil {
+		warnings = append(warnings,
+			"policy selects every non-system namespace and denies violating Secrets in all of them; consider a namespaceSelector or starting in audit mode")
+	}
+
+	return warnings
+}
+
+func policyGroupKind(policy compliancev1alpha1.PolicyObject) schema.GroupKind {
+	kind := "SecretPolicy"
+	if _, ok := policy.(*compliancev1alpha1.ClusterSecretPolicy); ok {
+		kind = "ClusterSecretPolicy"
+	}
+	return compliancev1alpha1.GroupVersion.WithKind(kind).GroupKind()
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel/codes"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,7 +32,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/metrics"
 	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -81,9 +86,7 @@ func (v *SecretPolicyValidator) ValidateCreate(_ context.Context, obj runtime.Ob
 	}
 	secretpolicylog.Info("Validation for SecretPolicy upon creation", "name", secretpolicy.GetName())
 
-	// TODO(user): fill in your validation logic upon object creation.
-
-	return nil, nil
+	return validateSecretPolicy(secretpolicy)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SecretPolicy.
@@ -94,9 +97,7 @@ func (v *SecretPolicyValidator) ValidateUpdate(_ context.Context, oldObj, newObj
 	}
 	secretpolicylog.Info("Validation for SecretPolicy upon update", "name", secretpolicy.GetName())
 
-	// TODO(user): fill in your validation logic upon object update.
-
-	return nil, nil
+	return validateSecretPolicy(secretpolicy)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SecretPolicy.
@@ -107,8 +108,7 @@ func (v *SecretPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.
 	}
 	secretpolicylog.Info("Validation for SecretPolicy upon deletion", "name", secretpolicy.GetName())
 
-	// TODO(user): fill in your validation logic upon object deletion.
-
+	// Deletion is always allowed; the controller finalizer cleans up after the policy.
 	return nil, nil
 }
 
@@ -121,6 +121,10 @@ func (v *SecretPolicyValidator) ValidateDelete(ctx context.Context, obj runtime.
 type SecretValidator struct {
 	Client  client.Client
 	Decoder admission.Decoder
+	// APIReader lists the workloads that reference a Secret being created.
+	// It reads from the API server, so that the webhook does not cache every
+	// Pod and workload of the cluster. Client is used when it is unset.
+	APIReader client.Reader
 }
 
 var _ admission.Handler = &SecretValidator{}
@@ -145,7 +149,7 @@ func (v *SecretValidator) InjectDecoder(decoder admission.Decoder) error {
 }
 
 func SetupSecretWebhookWithManager(mgr ctrl.Manager) error {
-	validator := &SecretValidator{}
+	validator := &SecretValidator{APIReader: mgr.GetAPIReader()}
 	// Inject client
 	if err := validator.InjectClient(mgr.GetClient()); err != nil {
 		return fmt.Errorf("failed to inject client: %w", err)
@@ -162,41 +166,199 @@ func SetupSecretWebhookWithManager(mgr ctrl.Manager) error {
 	return nil
 }
 
+// Handle validates a Secret against the policies that select it and records
+// the decision in the admission metrics and the request's span.
 func (v *SecretValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, span := tracing.Start(ctx, "SecretValidator.Handle",
+		tracing.KeyOperation.String(string(req.Operation)),
+		tracing.KeySecret.String(req.Namespace+"/"+req.Name),
+	)
+	defer span.End()
+
+	resp := v.handle(ctx, req)
+	decision := admissionDecision(resp)
+	metrics.AdmissionDecisions.WithLabelValues(string(req.Operation), decision).Inc()
+
+	span.SetAttributes(tracing.KeyDecision.String(decision))
+	if decision == metrics.DecisionError && resp.Result != nil {
+		span.SetStatus(codes.Error, resp.Result.Message)
+	}
+	return resp
+}
+
+// admissionDecision classifies a response for the admission metrics.
+// Requests the webhook failed to process are reported as "error".
+func admissionDecision(resp admission.Response) string {
+	switch {
+	case resp.Allowed && len(resp.Warnings) > 0:
+		return metrics.DecisionWarned
+	case resp.Allowed:
+		return metrics.DecisionAllowed
+	case resp.Result != nil && resp.Result.Code == http.StatusForbidden:
+		return metrics.DecisionDenied
+	default:
+		return metrics.DecisionError
+	}
+}
+
+func (v *SecretValidator) handle(ctx context.Context, req admission.Request) admission.Response {
 	secret := &corev1.Secret{}
 	if err := v.Decoder.Decode(req, secret); err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
 	// Skip system namespaces + empty
-	if secret.Namespace == "" ||
-		secret.Namespace == "kube-system" ||
-		secret.Namespace == "cert-manager" ||
-		secret.Namespace == "secret-policy-operator-system" {
-
+	if isSystemNamespace(secret.Namespace) {
 		return admission.Allowed("skipping validation for system namespace")
 	}
 
-	// List policies
-	var policies compliancev1alpha1.SecretPolicyList
-	if err := v.Client.List(ctx, &policies); err != nil {
+	listCtx, span := tracing.Start(ctx, "ListPolicies")
+	policies, err := selectingPolicies(listCtx, v.Client, secret)
+	tracing.End(span, err)
+	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
 
+	// Evaluate rotation against the record the mutating webhook stores, so
+	// that a hand-edited rotation annotation is never trusted
+	var old *corev1.Secret
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		old = &corev1.Secret{}
+		if err := v.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	internalpolicy.TrackRotation(secret, old, time.Now())
+
+	var exceptions compliancev1alpha1.SecretPolicyExceptionList
+	if err := v.Client.List(ctx, &exceptions, client.InNamespace(secret.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	now := time.Now()
+
 	var violations []string
-	for i := range policies.Items {
-		p := &policies.Items[i]
-		errs := internalpolicy.CheckSecretAgainstPolicy(secret, p)
-		for _, e := range errs {
-			violations = append(violations, e.Error())
+	var warnings admission.Warnings
+
+	for _, p := range policies {
+		if !actsAtAdmission(p) {
+			continue
 		}
+
+		ref := internalpolicy.PolicyRef(p)
+		start := time.Now()
+		found := internalpolicy.CheckSecretAgainstPolicyContext(ctx, secret, p, internalpolicy.WithReader(v.Client))
+		// Workloads referencing a Secret that did not exist yet were admitted
+		// without a consumer check, which happens once the Secret is created
+		if req.Operation == admissionv1.Create {
+			consumers, err := internalpolicy.CheckReferencingWorkloads(ctx, v.apiReader(), secret, p)
+			if err != nil {
+				return admission.Errored(http.StatusInternalServerError, err)
+			}
+			found = append(found, consumers...)
+		}
+		found, waived := internalpolicy.ApplyExceptions(found, secret, p, exceptions.Items, now)
+		metrics.ObserveEvaluation(metrics.SourceWebhook, p, found, waived, time.Since(start))
+		for _, w := range waived {
+			warnings = append(warnings, fmt.Sprintf("%s: waived by SecretPolicyException %s: %s", ref, w.Exception, w.Violation))
+		}
+
+		denied, warned := splitByAction(p, found)
+		violations = append(violations, denied...)
+		warnings = append(warnings, warned...)
 	}
 
 	if len(violations) > 0 {
 		return admission.Denied(
 			"Secret violates policy:\n - " + strings.Join(violations, "\n - "),
-		)
+		).WithWarnings(warnings...)
 	}
 
-	return admission.Allowed("valid secret")
+	return admission.Allowed("valid secret").WithWarnings(warnings...)
+}
+
+// apiReader returns the reader used to list the workloads of a namespace.
+func (v *SecretValidator) apiReader() client.Reader {
+	if v.APIReader != nil {
+		return v.APIReader
+	}
+	return v.Client
+}
+
+// isSystemNamespace reports whether Secrets in the namespace bypass the Secret
+// webhooks. Secrets without a namespace are skipped as well.
+func isSystemNamespace(namespace string) bool {
+	return namespace == "" ||
+		namespace == "kube-system" ||
+		namespace == "cert-manager" ||
+		namespace == "secret-policy-operator-system"
+}
+
+// actsAtAdmission reports whether the policy may deny, warn about or mutate
+// Secrets at admission. Audit-mode policies are only evaluated by the controller.
+func actsAtAdmission(p compliancev1alpha1.PolicyObject) bool {
+	return p.GetSpec().Action() != compliancev1alpha1.EnforcementActionAudit
+}
+
+// splitByAction renders the violations of a policy for the admission
+// response and splits them into those to deny and those to warn about. Only
+// enforced violations at or above the policy's threshold are denied.
+func splitByAction(p compliancev1alpha1.PolicyObject, found []compliancev1alpha1.Violation) (denied, warned []string) {
+	ref := internalpolicy.PolicyRef(p)
+	action := p.GetSpec().Action()
+	threshold := p.GetSpec().MinDenySeverity.Rank()
+	for _, violation := range found {
+		if action == compliancev1alpha1.EnforcementActionWarn || violation.Severity.Rank() < threshold {
+			warned = append(warned, fmt.Sprintf("%s: %s", ref, violation))
+			continue
+		}
+		denied = append(denied, fmt.Sprintf("%s: %s", ref, violation))
+	}
+	return denied, warned
+}
+
+// selectingPolicies returns the SecretPolicies and ClusterSecretPolicies that
+// are not disabled and select the Secret.
+func selectingPolicies(ctx context.Context, c client.Reader, secret *corev1.Secret) ([]compliancev1alpha1.PolicyObject, error) {
+	policies, err := internalpolicy.ListPolicies(ctx, c, secret.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsLabels map[string]string
+	nsLoaded := false
+
+	var active []compliancev1alpha1.PolicyObject
+	for _, p := range policies {
+		if p.GetSpec().Action() == compliancev1alpha1.EnforcementActionDisabled {
+			continue
+		}
+
+		// Namespace labels are only fetched when a policy selects on them
+		if p.GetSpec().NamespaceSelector != nil && !nsLoaded {
+			nsLabels, err = namespaceLabels(ctx, c, secret.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			nsLoaded = true
+		}
+
+		inScope, err := internalpolicy.SecretInScope(secret, nsLabels, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", internalpolicy.PolicyRef(p), err)
+		}
+		if inScope {
+			active = append(active, p)
+		}
+	}
+	return active, nil
+}
+
+// namespaceLabels returns the labels of the named namespace. A namespace that
+// does not exist yet is treated as having no labels.
+func namespaceLabels(ctx context.Context, c client.Reader, name string) (map[string]string, error) {
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, &ns); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return ns.Labels, nil
 }
@@ -17,10 +17,31 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/metrics"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
 	// TODO (user): Add any additional imports if needed
 )
 
@@ -32,8 +53,13 @@ var _ = Describe("SecretPolicy Webhook", func() {
 	)
 
 	BeforeEach(func() {
-		obj = &compliancev1alpha1.SecretPolicy{}
-		oldObj = &compliancev1alpha1.SecretPolicy{}
+		obj = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes: []string{string(corev1.SecretTypeOpaque), string(corev1.SecretTypeTLS)},
+			},
+		}
+		oldObj = obj.DeepCopy()
 		validator = SecretPolicyValidator{}
 		Expect(validator).NotTo(BeNil(), "Expected validator to be initialized")
 		Expect(oldObj).NotTo(BeNil(), "Expected oldObj to be initialized")
@@ -46,26 +72,577 @@ var _ = Describe("SecretPolicy Webhook", func() {
 	})
 
 	Context("When creating or updating SecretPolicy under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+		It("Should admit a policy whose CEL rules compile", func() {
+			obj.Spec.Rules = []compliancev1alpha1.CELRule{{
+				Name:       "tls-owner",
+				Expression: "secret.type != 'kubernetes.io/tls' || 'owner' in secret.metadata.labels",
+				Message:    "TLS Secrets must carry an owner label",
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation if a CEL rule does not compile", func() {
+			obj.Spec.Rules = []compliancev1alpha1.CELRule{{
+				Name:       "broken",
+				Expression: "secret.type ===",
+				Message:    "broken",
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[0].expression")))
+		})
+
+		It("Should deny updates introducing a non-boolean CEL rule", func() {
+			obj.Spec.Rules = []compliancev1alpha1.CELRule{{
+				Name:       "size",
+				Expression: "size(secret.keys)",
+				Message:    "size",
+			}}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should admit a valid policy without warnings", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny enabled rotation without a positive interval", func() {
+			obj.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.rotation.intervalDays")))
+
+			obj.Spec.Rotation.IntervalDays = -1
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should deny an unknown alerting method", func() {
+			obj.Spec.Alerting = compliancev1alpha1.AlertingSpec{EnableAlerts: true, Method: "pager"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.alerting.method")))
+		})
+
+		It("Should require alerting credentials from the policy's own namespace", func() {
+			obj.Spec.Alerting = compliancev1alpha1.AlertingSpec{EnableAlerts: true, Method: "slack"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.alerting.credentialsRef")))
+
+			obj.Spec.Alerting.CredentialsRef = &corev1.SecretReference{Name: "slack", Namespace: "other"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("own namespace")))
+
+			obj.Spec.Alerting.CredentialsRef.Namespace = ""
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an empty allowedTypes list", func() {
+			obj.Spec.AllowedTypes = nil
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("rejects every Secret")))
+		})
+
+		It("Should deny invalid and duplicate Secret types", func() {
+			obj.Spec.AllowedTypes = []string{"opaque", "kubernetes.io/tsl", "Opaque", "Opaque", "example.com/custom"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf("spec.allowedTypes[0]", "spec.allowedTypes[1]", "spec.allowedTypes[3]"))
+			Expect(err.Error()).To(ContainSubstring(`did you mean "Opaque"`))
+		})
+
+		It("Should deny duplicate keys and keys required by an allowed type", func() {
+			obj.Spec.DisallowedKeys = []string{"password", "password", "tls.key", "not/valid"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf("spec.disallowedKeys[1]", "spec.disallowedKeys[2]", "spec.disallowedKeys[3]"))
+		})
+
+		It("Should deny key patterns that do not compile or reject keys required by an allowed type", func() {
+			obj.Spec.DisallowedKeyPatterns = []string{".*[.]pem", "tls[.].*", "(", ".*[.]pem"}
+			obj.Spec.AllowedKeyPatterns = []string{"[a-z_]+"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				// tls.key and tls.crt each match the second pattern and none of the allowed ones
+				"spec.disallowedKeyPatterns[1]", "spec.disallowedKeyPatterns[1]",
+				"spec.disallowedKeyPatterns[2]", "spec.disallowedKeyPatterns[3]",
+				"spec.allowedKeyPatterns", "spec.allowedKeyPatterns",
+			))
+
+			obj.Spec.DisallowedKeyPatterns = []string{".*[.]pem"}
+			obj.Spec.AllowedKeyPatterns = []string{"[a-z_]+", "tls[.](crt|key)"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should warn about size limits that have no effect", func() {
+			obj.Spec.MaxValueBytes = 4096
+			obj.Spec.MaxTotalBytes = 2 << 20
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				ContainSubstring("rejects Secrets above 1048576 bytes"),
+			))
+
+			obj.Spec.MaxTotalBytes = 1024
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("maxValueBytes has no effect")))
+		})
+
+		It("Should deny invalid remediation labels and default types", func() {
+			obj.Spec.Remediation = &compliancev1alpha1.RemediationSpec{
+				Labels:      map[string]string{"owner": "not a valid value"},
+				DefaultType: "opaque",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.remediation.labels"),
+				ContainSubstring("spec.remediation.defaultType"),
+			)))
+		})
+
+		It("Should deny invalid content scan settings", func() {
+			threshold := resource.MustParse("9")
+			obj.Spec.ContentScan = &compliancev1alpha1.ContentScanSpec{
+				IgnoreKeys: []string{"config", "config"},
+				Credentials: &compliancev1alpha1.CredentialScanSpec{
+					Patterns:     []compliancev1alpha1.CredentialPattern{"jwt", "jwt"},
+					AllowedTypes: []string{"opaque"},
+				},
+				Entropy:       &compliancev1alpha1.EntropyScanSpec{Threshold: &threshold},
+				WeakPasswords: &compliancev1alpha1.WeakPasswordScanSpec{Keys: []string{"not/valid"}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"spec.contentScan.ignoreKeys[1]",
+				"spec.contentScan.credentials.patterns[1]",
+				"spec.contentScan.credentials.allowedTypes[0]",
+				"spec.contentScan.entropy.threshold",
+				"spec.contentScan.weakPasswords.keys[0]",
+			))
+		})
+
+		It("Should deny invalid TLS settings", func() {
+			obj.Spec.TLS = &compliancev1alpha1.TLSSpec{
+				ForbiddenSignatureAlgorithms: []string{"SHA1-RSA", "SHA1"},
+				RequiredSANs:                 []string{`.*\.example\.com`, "(unclosed"},
+				VerifyChain:                  true,
+				CABundle:                     "not a bundle",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"spec.tls.forbiddenSignatureAlgorithms[1]",
+				"spec.tls.requiredSANs[1]",
+				"spec.tls.caBundle",
+			))
+		})
+
+		It("Should deny malformed registries and duplicate SSH key algorithms", func() {
+			obj.Spec.Payload = &compliancev1alpha1.PayloadSpec{
+				AllowedRegistries: []string{"ghcr.io", "*.dkr.ecr.eu-west-1.amazonaws.com", "registry.local:5000",
+					"https://ghcr.io", "registry.local:http", "ghcr.io"},
+				AllowedSSHKeyAlgorithms: []compliancev1alpha1.SSHKeyAlgorithm{"ed25519", "ed25519"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"spec.payload.allowedRegistries[3]",
+				"spec.payload.allowedRegistries[4]",
+				"spec.payload.allowedRegistries[5]",
+				"spec.payload.allowedSSHKeyAlgorithms[1]",
+			))
+		})
+
+		It("Should deny malformed allowed ServiceAccounts and warn that they are not enforced at admission", func() {
+			obj.Spec.AccessRules.AllowedServiceAccounts = []string{"api", "ops/*", "ops/backup", "*", "Ops/backup", "api"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"spec.accessRules.allowedServiceAccounts[3]",
+				"spec.accessRules.allowedServiceAccounts[4]",
+				"spec.accessRules.allowedServiceAccounts[5]",
+			))
+
+			obj.Spec.AccessRules.AllowedServiceAccounts = []string{"api", "ops/*"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("reported by scans only")))
+		})
+
+		It("Should deny malformed ignored types of unused detection and warn that it is not enforced at admission", func() {
+			obj.Spec.Unused = &compliancev1alpha1.UnusedSpec{
+				IgnoredTypes: []string{"example.com/token", "opaque", "example.com/token"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			status := err.(apierrors.APIStatus).Status()
+			fields := []string{}
+			for _, cause := range status.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf("spec.unused.ignoredTypes[1]", "spec.unused.ignoredTypes[2]"))
+
+			obj.Spec.Unused.IgnoredTypes = []string{"example.com/token"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("reported by scans only")))
+		})
+
+		It("Should warn when TLS checks cannot apply", func() {
+			obj.Spec.AllowedTypes = []string{string(corev1.SecretTypeOpaque)}
+			obj.Spec.TLS = &compliancev1alpha1.TLSSpec{MinDaysToExpiry: 30}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("kubernetes.io/tls is not in allowedTypes")))
+		})
+
+		It("Should warn about risky but legal settings", func() {
+			obj.Spec.Rotation = compliancev1alpha1.RotationSpec{IntervalDays: 30}
+			obj.Spec.AccessRules.AllowedNamespaces = []string{"prod"}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				ContainSubstring("rotation is not enabled"),
+				ContainSubstring("does not include apps"),
+			))
+		})
+
+		It("Should apply the same validation to ClusterSecretPolicy", func() {
+			cluster := &compliancev1alpha1.ClusterSecretPolicy{}
+			cluster.Spec.Rules = []compliancev1alpha1.CELRule{{
+				Name:       "broken",
+				Expression: "secret.type ===",
+				Message:    "broken",
+			}}
+			_, err := (&ClusterSecretPolicyValidator{}).ValidateCreate(ctx, cluster)
+			Expect(err).To(MatchError(ContainSubstring("ClusterSecretPolicy")))
+		})
+
+		It("Should warn when a ClusterSecretPolicy is enforced in every namespace", func() {
+			cluster := &compliancev1alpha1.ClusterSecretPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "baseline"},
+				Spec:       *obj.Spec.DeepCopy(),
+			}
+			warnings, err := (&ClusterSecretPolicyValidator{}).ValidateCreate(ctx, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("selects every non-system namespace")))
+		})
+	})
+
+})
+
+var _ = Describe("Secret Webhook", func() {
+	var secret *corev1.Secret
+
+	newPolicy := func(name string, action compliancev1alpha1.EnforcementAction) *compliancev1alpha1.SecretPolicy {
+		return &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				EnforcementAction: action,
+				AllowedTypes:      []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys:    []string{"password"},
+				AccessRules: compliancev1alpha1.AccessRulesSpec{
+					AllowedNamespaces: []string{"apps"},
+				},
+			},
+		}
+	}
+
+	handle := func(objs ...client.Object) admission.Response {
+		validator := &SecretValidator{
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			Decoder: admission.NewDecoder(scheme.Scheme),
+		}
+		return validator.Handle(ctx, newSecretRequest(secret))
+	}
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		}
 	})
 
+	Context("When evaluating enforcement actions", func() {
+		It("Should deny a violating Secret for an enforce policy", func() {
+			resp := handle(newPolicy("strict", compliancev1alpha1.EnforcementActionEnforce))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("key password is disallowed"))
+		})
+
+		It("Should treat an unset action as enforce", func() {
+			resp := handle(newPolicy("default", ""))
+			Expect(resp.Allowed).To(BeFalse())
+		})
+
+		It("Should allow with warnings for a warn policy", func() {
+			resp := handle(newPolicy("staged", compliancev1alpha1.EnforcementActionWarn))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ContainElement(ContainSubstring("SecretPolicy apps/staged: [high] disallowedKeys: key password is disallowed")))
+		})
+
+		It("Should only deny violations at or above minDenySeverity", func() {
+			p := newPolicy("critical-only", compliancev1alpha1.EnforcementActionEnforce)
+			p.Spec.MinDenySeverity = compliancev1alpha1.SeverityCritical
+			resp := handle(p)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ContainElement(ContainSubstring("[high] disallowedKeys")))
+
+			p.Spec.MinDenySeverity = compliancev1alpha1.SeverityHigh
+			Expect(handle(p).Allowed).To(BeFalse())
+		})
+
+		It("Should deny Secrets above the size limits", func() {
+			p := newPolicy("small", compliancev1alpha1.EnforcementActionEnforce)
+			p.Spec.DisallowedKeys = nil
+			p.Spec.MaxValueBytes = 4
+			resp := handle(p)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("maxValueBytes: key password holds 7 bytes, at most 4 are allowed"))
+		})
+
+		It("Should allow silently for audit and disabled policies", func() {
+			resp := handle(
+				newPolicy("audited", compliancev1alpha1.EnforcementActionAudit),
+				newPolicy("off", compliancev1alpha1.EnforcementActionDisabled),
+			)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When recording admission metrics", func() {
+		decisions := func(decision string) float64 {
+			return testutil.ToFloat64(metrics.AdmissionDecisions.WithLabelValues("CREATE", decision))
+		}
+
+		It("Should count denied, warned and allowed requests", func() {
+			denied, warned, allowed := decisions(metrics.DecisionDenied), decisions(metrics.DecisionWarned), decisions(metrics.DecisionAllowed)
+
+			handle(newPolicy("strict", compliancev1alpha1.EnforcementActionEnforce))
+			handle(newPolicy("staged", compliancev1alpha1.EnforcementActionWarn))
+			handle(newPolicy("audited", compliancev1alpha1.EnforcementActionAudit))
+
+			Expect(decisions(metrics.DecisionDenied)).To(Equal(denied + 1))
+			Expect(decisions(metrics.DecisionWarned)).To(Equal(warned + 1))
+			Expect(decisions(metrics.DecisionAllowed)).To(Equal(allowed + 1))
+		})
+
+		It("Should count requests that could not be processed as errors", func() {
+			Expect(admissionDecision(admission.Errored(http.StatusInternalServerError, errors.New("boom")))).To(Equal(metrics.DecisionError))
+		})
+	})
+
+	Context("When tracing admission requests", func() {
+		It("Should record the decision with policy listing and evaluation as child spans", func() {
+			recorder := tracetest.NewSpanRecorder()
+			previous := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			DeferCleanup(otel.SetTracerProvider, previous)
+
+			handle(newPolicy("strict", compliancev1alpha1.EnforcementActionEnforce))
+
+			spans := map[string]sdktrace.ReadOnlySpan{}
+			for _, span := range recorder.Ended() {
+				spans[span.Name()] = span
+			}
+			Expect(spans).To(HaveKey("SecretValidator.Handle"))
+			root := spans["SecretValidator.Handle"]
+			Expect(root.Attributes()).To(ContainElement(tracing.KeyDecision.String(metrics.DecisionDenied)))
+
+			for _, name := range []string{"ListPolicies", "CheckSecretAgainstPolicy"} {
+				Expect(spans).To(HaveKey(name))
+				Expect(spans[name].Parent().SpanID()).To(Equal(root.SpanContext().SpanID()))
+			}
+			Expect(spans["CheckSecretAgainstPolicy"].Attributes()).To(ContainElement(tracing.KeyPolicy.String("SecretPolicy apps/strict")))
+		})
+	})
+
+	Context("When enforcing rotation", func() {
+		It("Should ignore a hand-set lastRotated annotation on update", func() {
+			policy := newPolicy("rotation", compliancev1alpha1.EnforcementActionEnforce)
+			policy.Spec.DisallowedKeys = nil
+			policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30}
+
+			old := secret.DeepCopy()
+			old.CreationTimestamp = metav1.NewTime(time.Now().AddDate(-1, 0, 0))
+			secret.Annotations = map[string]string{"lastRotated": time.Now().Format(time.RFC3339)}
+
+			req := newSecretRequest(secret)
+			raw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			req.Operation = admissionv1.Update
+			req.OldObject = runtime.RawExtension{Raw: raw}
+
+			validator := &SecretValidator{
+				Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(policy).Build(),
+				Decoder: admission.NewDecoder(scheme.Scheme),
+			}
+			resp := validator.Handle(ctx, req)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("rotation interval exceeded"))
+		})
+
+		It("Should admit new Secrets", func() {
+			policy := newPolicy("rotation", compliancev1alpha1.EnforcementActionEnforce)
+			policy.Spec.DisallowedKeys = nil
+			policy.Spec.Rotation = compliancev1alpha1.RotationSpec{Enabled: true, IntervalDays: 30}
+
+			Expect(handle(policy).Allowed).To(BeTrue())
+		})
+	})
+
+	Context("When checking typed payloads", func() {
+		It("Should deny token Secrets of ServiceAccounts that do not exist", func() {
+			policy := newPolicy("payload", compliancev1alpha1.EnforcementActionEnforce)
+			policy.Spec.DisallowedKeys = nil
+			policy.Spec.AllowedTypes = []string{string(corev1.SecretTypeServiceAccountToken)}
+			policy.Spec.Payload = &compliancev1alpha1.PayloadSpec{}
+			secret.Type = corev1.SecretTypeServiceAccountToken
+			secret.Annotations = map[string]string{corev1.ServiceAccountNameKey: "builder"}
+
+			resp := handle(policy)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("ServiceAccount builder does not exist"))
+
+			sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "apps"}}
+			Expect(handle(policy, sa).Allowed).To(BeTrue())
+		})
+	})
+
+	Context("When combining namespaced and cluster-scoped policies", func() {
+		It("Should ignore SecretPolicies from other namespaces", func() {
+			p := newPolicy("elsewhere", compliancev1alpha1.EnforcementActionEnforce)
+			p.Namespace = "other"
+			Expect(handle(p).Allowed).To(BeTrue())
+		})
+
+		It("Should evaluate ClusterSecretPolicies for every namespace", func() {
+			cluster := &compliancev1alpha1.ClusterSecretPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "baseline"},
+				Spec:       newPolicy("baseline", "").Spec,
+			}
+			resp := handle(cluster)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("key password is disallowed"))
+		})
+	})
+
+	Context("When a SecretPolicyException applies", func() {
+		var exception *compliancev1alpha1.SecretPolicyException
+
+		BeforeEach(func() {
+			secret.Labels = map[string]string{"app": "legacy"}
+			exception = &compliancev1alpha1.SecretPolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "waiver", Namespace: "apps"},
+				Spec: compliancev1alpha1.SecretPolicyExceptionSpec{
+					PolicyRef:      compliancev1alpha1.PolicyReference{Name: "strict"},
+					Rules:          []string{"disallowedKeys"},
+					SecretSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}},
+					ExpiresAt:      metav1.NewTime(time.Now().Add(time.Hour)),
+					Justification:  "migration in progress",
+				},
+			}
+		})
+
+		It("Should admit the Secret and warn about the waiver", func() {
+			resp := handle(newPolicy("strict", compliancev1alpha1.EnforcementActionEnforce), exception)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ContainElement(ContainSubstring("waived by SecretPolicyException apps/waiver")))
+		})
+
+		It("Should deny again once the exception has expired", func() {
+			exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Minute))
+			resp := handle(newPolicy("strict", compliancev1alpha1.EnforcementActionEnforce), exception)
+			Expect(resp.Allowed).To(BeFalse())
+		})
+	})
+
+	Context("When scoping policies with selectors", func() {
+		It("Should ignore policies whose namespaceSelector does not match", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "apps",
+				Labels: map[string]string{"team": "b"},
+			}}
+			p := newPolicy("team-a", compliancev1alpha1.EnforcementActionEnforce)
+			p.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+
+			Expect(handle(ns, p).Allowed).To(BeTrue())
+
+			ns.Labels["team"] = "a"
+			Expect(handle(ns, p).Allowed).To(BeFalse())
+		})
+
+		It("Should ignore policies whose secretSelector does not match", func() {
+			p := newPolicy("labelled", compliancev1alpha1.EnforcementActionEnforce)
+			p.Spec.SecretSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "db"}}
+
+			Expect(handle(p).Allowed).To(BeTrue())
+
+			secret.Labels = map[string]string{"tier": "db"}
+			Expect(handle(p).Allowed).To(BeFalse())
+		})
+	})
 })
+
+// newSecretRequest wraps a Secret in an admission request as the API server would send it.
+func newSecretRequest(secret *corev1.Secret) admission.Request {
+	raw, err := json.Marshal(secret)
+	Expect(err).NotTo(HaveOccurred())
+
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+// log is for logging in this package.
+var secretpolicyexceptionlog = logf.Log.WithName("secretpolicyexception-resource")
+
+// DefaultMaxExceptionDuration is how far in the future expiresAt may lie by
+// default.
+const DefaultMaxExceptionDuration = 90 * 24 * time.Hour
+
+// +kubebuilder:webhook:path=/validate-compliance-security-local-v1alpha1-secretpolicyexception,mutating=false,failurePolicy=fail,sideEffects=None,groups=compliance.security.local,resources=secretpolicyexceptions,verbs=create;update,versions=v1alpha1,name=vsecretpolicyexception-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SecretPolicyExceptionValidator rejects exceptions that could never take
+// effect, because their policy does not exist, cannot be waived or does not
+// check the waived rules, and exceptions that expire too late.
+type SecretPolicyExceptionValidator struct {
+	Client client.Reader
+	// MaxDuration caps how far in the future expiresAt may be set; zero
+	// disables the cap.
+	MaxDuration time.Duration
+}
+
+var _ webhook.CustomValidator = &SecretPolicyExceptionValidator{}
+
+// SetupSecretPolicyExceptionWebhookWithManager registers the webhook for SecretPolicyException in the manager.
+func SetupSecretPolicyExceptionWebhookWithManager(mgr ctrl.Manager, maxDuration time.Duration) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&compliancev1alpha1.SecretPolicyException{}).
+		WithValidator(&SecretPolicyExceptionValidator{Client: mgr.GetClient(), MaxDuration: maxDuration}).
+		Complete()
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SecretPolicyException.
+func (v *SecretPolicyExceptionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	exc, ok := obj.(*compliancev1alpha1.SecretPolicyException)
+	if !ok {
+		return nil, fmt.Errorf("expected a SecretPolicyException object but got %T", obj)
+	}
+	secretpolicyexceptionlog.Info("Validation for SecretPolicyException upon creation", "name", exc.GetName())
+
+	return v.validate(ctx, exc, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SecretPolicyException.
+func (v *SecretPolicyExceptionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	exc, ok := newObj.(*compliancev1alpha1.SecretPolicyException)
+	if !ok {
+		return nil, fmt.Errorf("expected a SecretPolicyException object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*compliancev1alpha1.SecretPolicyException)
+	if !ok {
+		return nil, fmt.Errorf("expected a SecretPolicyException object for the oldObj but got %T", oldObj)
+	}
+	secretpolicyexceptionlog.Info("Validation for SecretPolicyException upon update", "name", exc.GetName())
+
+	return v.validate(ctx, exc, old)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SecretPolicyException.
+func (v *SecretPolicyExceptionValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the exception against its policy. The expiry is only
+// capped when it is set or changed, so that existing exceptions stay
+// editable.
+func (v *SecretPolicyExceptionValidator) validate(
+	ctx context.Context,
+	exc *compliancev1alpha1.SecretPolicyException,
+	old *compliancev1alpha1.SecretPolicyException,
+) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	policy, errs, err := v.referencedPolicy(ctx, specPath.Child("policyRef"), exc)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, errs...)
+	if policy != nil {
+		allErrs = append(allErrs, validateWaivedRules(specPath.Child("rules"), exc.Spec.Rules, policy)...)
+	}
+	allErrs = append(allErrs, validateSelector(specPath.Child("secretSelector"), &exc.Spec.SecretSelector)...)
+	if old == nil || !exc.Spec.ExpiresAt.Equal(&old.Spec.ExpiresAt) {
+		allErrs = append(allErrs, v.validateExpiry(specPath.Child("expiresAt"), exc.Spec.ExpiresAt.Time, time.Now())...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(compliancev1alpha1.GroupVersion.WithKind("SecretPolicyException").GroupKind(), exc.Name, allErrs)
+}
+
+// referencedPolicy returns the policy the exception waives, or field errors
+// when it does not exist or cannot be waived by the exception.
+func (v *SecretPolicyExceptionValidator) referencedPolicy(
+	ctx context.Context,
+	fldPath *field.Path,
+	exc *compliancev1alpha1.SecretPolicyException,
+) (compliancev1alpha1.PolicyObject, field.ErrorList, error) {
+	ref := exc.Spec.PolicyRef
+	var policy compliancev1alpha1.PolicyObject
+	var key client.ObjectKey
+	if ref.Kind == "ClusterSecretPolicy" {
+		policy, key = &compliancev1alpha1.ClusterSecretPolicy{}, client.ObjectKey{Name: ref.Name}
+	} else {
+		policy, key = &compliancev1alpha1.SecretPolicy{}, client.ObjectKey{Namespace: exc.Namespace, Name: ref.Name}
+	}
+
+	err := v.Client.Get(ctx, key, policy)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, field.ErrorList{field.NotFound(fldPath.Child("name"), ref.Name)}, nil
+	case err != nil:
+		return nil, nil, err
+	}
+	if !internalpolicy.ExceptionTargets(exc, policy) {
+		return nil, field.ErrorList{field.Forbidden(fldPath,
+			fmt.Sprintf("ClusterSecretPolicy %s does not set allowNamespacedExceptions", ref.Name))}, nil
+	}
+	return policy, nil, nil
+}
+
+// validateWaivedRules rejects duplicates and rules the policy does not check,
+// which could never be waived.
+func validateWaivedRules(fldPath *field.Path, rules []string, policy compliancev1alpha1.PolicyObject) field.ErrorList {
+	allErrs := validateUnique(fldPath, rules)
+	active := internalpolicy.ActiveRules(policy, internalpolicy.WithAccessAnalysis())
+	for i, rule := range rules {
+		if rule != internalpolicy.WaiveAllRules && !slices.Contains(active, rule) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), rule, append([]string{internalpolicy.WaiveAllRules}, active...)))
+		}
+	}
+	return allErrs
+}
+
+// validateExpiry requires expiresAt to lie in the future, and at most
+// MaxDuration from now.
+func (v *SecretPolicyExceptionValidator) validateExpiry(fldPath *field.Path, expiresAt, now time.Time) field.ErrorList {
+	if !expiresAt.After(now) {
+		return field.ErrorList{field.Invalid(fldPath, expiresAt.UTC().Format(time.RFC3339), "must be in the future")}
+	}
+	if v.MaxDuration > 0 && expiresAt.After(now.Add(v.MaxDuration)) {
+		return field.ErrorList{field.Invalid(fldPath, expiresAt.UTC().Format(time.RFC3339),
+			fmt.Sprintf("must be at most %s from now; renew the exception before it expires instead", formatDuration(v.MaxDuration)))}
+	}
+	return nil
+}
+
+// formatDuration formats whole days as such, e.g. "90 days".
+func formatDuration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+)
+
+var _ = Describe("SecretPolicyException Webhook", func() {
+	var (
+		policy    *compliancev1alpha1.SecretPolicy
+		cluster   *compliancev1alpha1.ClusterSecretPolicy
+		exception *compliancev1alpha1.SecretPolicyException
+	)
+
+	BeforeEach(func() {
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "strict", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				DisallowedKeys: []string{"password"},
+				Rules:          []compliancev1alpha1.CELRule{{Name: "owner", Expression: "has(object.metadata.labels.owner)"}},
+			},
+		}
+		cluster = &compliancev1alpha1.ClusterSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline"},
+			Spec:       compliancev1alpha1.SecretPolicySpec{AllowedTypes: []string{string(corev1.SecretTypeOpaque)}},
+		}
+		exception = &compliancev1alpha1.SecretPolicyException{
+			ObjectMeta: metav1.ObjectMeta{Name: "waiver", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicyExceptionSpec{
+				PolicyRef:      compliancev1alpha1.PolicyReference{Kind: "SecretPolicy", Name: "strict"},
+				Rules:          []string{internalpolicy.RuleDisallowedKeys, "cel.owner"},
+				SecretSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}},
+				ExpiresAt:      metav1.NewTime(time.Now().Add(7 * 24 * time.Hour)),
+				Justification:  "migration in progress",
+			},
+		}
+	})
+
+	validator := func(objs ...client.Object) *SecretPolicyExceptionValidator {
+		return &SecretPolicyExceptionValidator{
+			Client:      fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			MaxDuration: DefaultMaxExceptionDuration,
+		}
+	}
+
+	invalidFields := func(err error) []string {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+		var fields []string
+		for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		return fields
+	}
+
+	It("Should admit exceptions for rules their policy checks", func() {
+		Expect(validator(policy).ValidateCreate(ctx, exception)).Error().NotTo(HaveOccurred())
+
+		exception.Spec.Rules = []string{internalpolicy.WaiveAllRules}
+		Expect(validator(policy).ValidateCreate(ctx, exception)).Error().NotTo(HaveOccurred())
+	})
+
+	It("Should reject unknown rule IDs and rules the policy does not check", func() {
+		exception.Spec.Rules = []string{"disallowedKey", internalpolicy.RuleRotation, internalpolicy.RuleDisallowedKeys}
+		_, err := validator(policy).ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.rules[0]", "spec.rules[1]"}))
+	})
+
+	It("Should reject exceptions for policies that do not exist", func() {
+		_, err := validator().ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.policyRef.name"}))
+
+		// A SecretPolicy must live in the exception's namespace
+		policy.Namespace = "other"
+		_, err = validator(policy).ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.policyRef.name"}))
+	})
+
+	It("Should reject exceptions for ClusterSecretPolicies that do not allow them", func() {
+		exception.Spec.PolicyRef = compliancev1alpha1.PolicyReference{Kind: "ClusterSecretPolicy", Name: "baseline"}
+		exception.Spec.Rules = []string{internalpolicy.RuleAllowedTypes}
+		_, err := validator(cluster).ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.policyRef"}))
+
+		cluster.Spec.AllowNamespacedExceptions = true
+		Expect(validator(cluster).ValidateCreate(ctx, exception)).Error().NotTo(HaveOccurred())
+	})
+
+	It("Should cap how far in the future exceptions expire", func() {
+		exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(DefaultMaxExceptionDuration + time.Hour))
+		_, err := validator(policy).ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.expiresAt"}))
+		Expect(err.Error()).To(ContainSubstring("must be at most 90 days from now"))
+
+		exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		_, err = validator(policy).ValidateCreate(ctx, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.expiresAt"}))
+
+		unlimited := validator(policy)
+		unlimited.MaxDuration = 0
+		exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(5 * 365 * 24 * time.Hour))
+		Expect(unlimited.ValidateCreate(ctx, exception)).Error().NotTo(HaveOccurred())
+	})
+
+	It("Should only check the expiry on update when it changes", func() {
+		old := exception.DeepCopy()
+		old.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		exception.Spec.ExpiresAt = old.Spec.ExpiresAt
+		exception.Spec.Justification = "expired, kept for the audit trail"
+		Expect(validator(policy).ValidateUpdate(ctx, old, exception)).Error().NotTo(HaveOccurred())
+
+		exception.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(DefaultMaxExceptionDuration + time.Hour))
+		_, err := validator(policy).ValidateUpdate(ctx, old, exception)
+		Expect(invalidFields(err)).To(Equal([]string{"spec.expiresAt"}))
+	})
+})
@@ -112,6 +112,12 @@ var _ = BeforeSuite(func() {
 	err = SetupSecretPolicyWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = SetupClusterSecretPolicyWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupSecretPolicyExceptionWebhookWithManager(mgr, DefaultMaxExceptionDuration)
+	Expect(err).NotTo(HaveOccurred())
+
 	// +kubebuilder:scaffold:webhook
 
 	go func() {
@@ -0,0 +1,235 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/metrics"
+	internalpolicy "github.com/Kisor-S/secret-policy-operator/internal/policy"
+	"github.com/Kisor-S/secret-policy-operator/internal/tracing"
+)
+
+// -----------------------------------------------------------------------------
+// WorkloadValidator – admission webhook for Pods and pod templates
+// -----------------------------------------------------------------------------
+
+// The webhook is installed failing open, so that Pods can start before the
+// operator does; the workload webhook controller switches it to Fail while an
+// enforce policy restricts ServiceAccounts. System namespaces and the
+// operator's own are excluded in config/webhook, so that an outage never
+// blocks the operator's Pods.
+// +kubebuilder:webhook:path=/validate-v1-workload,mutating=false,failurePolicy=ignore,sideEffects=None,groups="";apps;batch,resources=pods;deployments;replicasets;statefulsets;daemonsets;jobs;cronjobs,verbs=create;update,versions=v1,name=workload.validator.kishore.dev,admissionReviewVersions=v1
+
+// WorkloadValidator rejects Pods, and workloads with a Pod template, that
+// consume a Secret as a ServiceAccount the policies governing the Secret do
+// not allow in accessRules.allowedServiceAccounts. It is the admission
+// counterpart of the controller's accessRules.consumers rule.
+type WorkloadValidator struct {
+	Client  client.Client
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &WorkloadValidator{}
+
+// SetupWorkloadWebhookWithManager registers the workload webhook in the manager.
+func SetupWorkloadWebhookWithManager(mgr ctrl.Manager) error {
+	validator := &WorkloadValidator{
+		Client:  mgr.GetClient(),
+		Decoder: admission.NewDecoder(mgr.GetScheme()),
+	}
+
+	mgr.GetWebhookServer().Register("/validate-v1-workload",
+		&admission.Webhook{Handler: validator})
+	return nil
+}
+
+// Handle validates the Secret consumers of a Pod or pod template and
+// records the decision in the admission metrics and the request's span.
+func (v *WorkloadValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx, span := tracing.Start(ctx, "WorkloadValidator.Handle",
+		tracing.KeyOperation.String(string(req.Operation)),
+		tracing.KeyWorkload.String(req.Kind.Kind+" "+req.Namespace+"/"+req.Name),
+	)
+	defer span.End()
+
+	resp := v.handle(ctx, req)
+	decision := admissionDecision(resp)
+	metrics.WorkloadAdmissionDecisions.WithLabelValues(req.Kind.Kind, string(req.Operation), decision).Inc()
+
+	span.SetAttributes(tracing.KeyDecision.String(decision))
+	if decision == metrics.DecisionError && resp.Result != nil {
+		span.SetStatus(codes.Error, resp.Result.Message)
+	}
+	return resp
+}
+
+func (v *WorkloadValidator) handle(ctx context.Context, req admission.Request) admission.Response {
+	if isSystemNamespace(req.Namespace) {
+		return admission.Allowed("skipping validation for system namespace")
+	}
+
+	// The containers, volumes and ServiceAccount of a Pod cannot change
+	// after creation; ephemeral containers are added through a subresource
+	if req.Kind.Kind == "Pod" && req.Operation != admissionv1.Create {
+		return admission.Allowed("Pod consumers are checked on create")
+	}
+
+	workload, spec, err := v.podTemplate(req.Kind.Kind, req.Object)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if spec == nil {
+		return admission.Allowed("no Pod template")
+	}
+
+	// Violations the workload already had are left to the controller, so
+	// that scaling or relabeling an existing workload is never blocked
+	var oldSpec *corev1.PodSpec
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		if _, oldSpec, err = v.podTemplate(req.Kind.Kind, req.OldObject); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	var exceptions compliancev1alpha1.SecretPolicyExceptionList
+	if err := v.Client.List(ctx, &exceptions, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	now := time.Now()
+
+	var violations []string
+	var warnings []string
+	for _, name := range internalpolicy.ReferencedSecrets(spec) {
+		secret, err := v.secretMetadata(ctx, req.Namespace, name)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		// A Secret created later is checked against this workload by the
+		// Secret webhook
+		if secret == nil {
+			continue
+		}
+
+		policies, err := selectingPolicies(ctx, v.Client, secret)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		for _, p := range policies {
+			if !actsAtAdmission(p) {
+				continue
+			}
+
+			found := internalpolicy.CheckConsumer(secret, workload, spec, p)
+			if oldSpec != nil && len(found) > 0 && len(internalpolicy.CheckConsumer(secret, workload, oldSpec, p)) > 0 {
+				continue
+			}
+			found, waived := internalpolicy.ApplyExceptions(found, secret, p, exceptions.Items, now)
+			for _, w := range waived {
+				warnings = append(warnings, fmt.Sprintf("%s: waived by SecretPolicyException %s: Secret %s: %s",
+					internalpolicy.PolicyRef(p), w.Exception, name, w.Violation))
+			}
+
+			denied, warned := splitByAction(p, found)
+			for _, d := range denied {
+				violations = append(violations, "Secret "+name+": "+d)
+			}
+			for _, w := range warned {
+				warnings = append(warnings, "Secret "+name+": "+w)
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return admission.Denied(
+			req.Kind.Kind + " consumes Secrets against policy:\n - " + strings.Join(violations, "\n - "),
+		).WithWarnings(warnings...)
+	}
+	return admission.Allowed("valid Secret consumers").WithWarnings(warnings...)
+}
+
+// podTemplate decodes a Pod or a workload of the given kind and returns its
+// name for messages and its Pod spec. Other kinds return a nil spec.
+func (v *WorkloadValidator) podTemplate(kind string, raw runtime.RawExtension) (string, *corev1.PodSpec, error) {
+	var obj client.Object
+	var spec *corev1.PodSpec
+	switch kind {
+	case "Pod":
+		pod := &corev1.Pod{}
+		obj, spec = pod, &pod.Spec
+	case "Deployment":
+		d := &appsv1.Deployment{}
+		obj, spec = d, &d.Spec.Template.Spec
+	case "ReplicaSet":
+		rs := &appsv1.ReplicaSet{}
+		obj, spec = rs, &rs.Spec.Template.Spec
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		obj, spec = sts, &sts.Spec.Template.Spec
+	case "DaemonSet":
+		ds := &appsv1.DaemonSet{}
+		obj, spec = ds, &ds.Spec.Template.Spec
+	case "Job":
+		job := &batchv1.Job{}
+		obj, spec = job, &job.Spec.Template.Spec
+	case "CronJob":
+		cj := &batchv1.CronJob{}
+		obj, spec = cj, &cj.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return "", nil, nil
+	}
+
+	if err := v.Decoder.DecodeRaw(raw, obj); err != nil {
+		return "", nil, err
+	}
+	if pod, ok := obj.(*corev1.Pod); ok {
+		return internalpolicy.WorkloadRef(pod), spec, nil
+	}
+	return kind + " " + obj.GetName(), spec, nil
+}
+
+// secretMetadata returns a Secret holding only the metadata of the named
+// Secret, which is all policy selection needs, or nil if it does not exist.
+// Reading metadata only keeps the data of Secrets out of the cache.
+func (v *WorkloadValidator) secretMetadata(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	meta := &metav1.PartialObjectMetadata{}
+	meta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, meta); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &corev1.Secret{ObjectMeta: meta.ObjectMeta}, nil
+}
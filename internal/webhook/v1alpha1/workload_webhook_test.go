@@ -0,0 +1,222 @@
+/*
+Copyright 2025 Kishore.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	compliancev1alpha1 "github.com/Kisor-S/secret-policy-operator/api/v1alpha1"
+	"github.com/Kisor-S/secret-policy-operator/internal/metrics"
+)
+
+var _ = Describe("Workload Webhook", func() {
+	var (
+		secret *corev1.Secret
+		policy *compliancev1alpha1.SecretPolicy
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps", Labels: map[string]string{"app": "db"}},
+			Type:       corev1.SecretTypeOpaque,
+		}
+		policy = &compliancev1alpha1.SecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-access", Namespace: "apps"},
+			Spec: compliancev1alpha1.SecretPolicySpec{
+				SecretSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				AllowedTypes:   []string{string(corev1.SecretTypeOpaque)},
+				AccessRules: compliancev1alpha1.AccessRulesSpec{
+					AllowedServiceAccounts: []string{"api"},
+				},
+			},
+		}
+	})
+
+	podSpec := func(sa string, secrets ...string) corev1.PodSpec {
+		spec := corev1.PodSpec{
+			ServiceAccountName: sa,
+			Containers:         []corev1.Container{{Name: "app", Image: "app"}},
+		}
+		for _, name := range secrets {
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name:         name,
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+			})
+		}
+		return spec
+	}
+
+	request := func(op admissionv1.Operation, kind string, obj, old client.Object) admission.Request {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: kind},
+			Name:      obj.GetName(),
+			Namespace: "apps",
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+		if old != nil {
+			oldRaw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: oldRaw}
+		}
+		return req
+	}
+
+	handle := func(req admission.Request, objs ...client.Object) admission.Response {
+		validator := &WorkloadValidator{
+			Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+			Decoder: admission.NewDecoder(scheme.Scheme),
+		}
+		return validator.Handle(ctx, req)
+	}
+
+	deployment := func(sa string, secrets ...string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+			Spec:       appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: podSpec(sa, secrets...)}},
+		}
+	}
+
+	Context("When admitting Pods", func() {
+		It("Should deny Pods that consume a governed Secret as a ServiceAccount that is not allowed", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{GenerateName: "web-", Namespace: "apps"}, Spec: podSpec("web", "db")}
+			resp := handle(request(admissionv1.Create, "Pod", pod, nil), secret, policy)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring(
+				"Secret db: SecretPolicy apps/db-access: [high] accessRules.consumers: Pod web-* consumes the Secret (volume) as ServiceAccount web, which is not allowed"))
+
+			pod.Spec.ServiceAccountName = "api"
+			Expect(handle(request(admissionv1.Create, "Pod", pod, nil), secret, policy).Allowed).To(BeTrue())
+		})
+
+		It("Should allow Pods whose Secrets are not governed or do not exist", func() {
+			other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "apps"}}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}, Spec: podSpec("web", "cache", "missing")}
+			Expect(handle(request(admissionv1.Create, "Pod", pod, nil), other, secret, policy).Allowed).To(BeTrue())
+
+			policy.Spec.AccessRules.AllowedServiceAccounts = nil
+			pod.Spec = podSpec("web", "db")
+			Expect(handle(request(admissionv1.Create, "Pod", pod, nil), secret, policy).Allowed).To(BeTrue())
+		})
+
+		It("Should only check Pods on create", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}, Spec: podSpec("web", "db")}
+			Expect(handle(request(admissionv1.Update, "Pod", pod, pod), secret, policy).Allowed).To(BeTrue())
+		})
+	})
+
+	Context("When admitting workloads", func() {
+		It("Should check the Pod template of workloads", func() {
+			resp := handle(request(admissionv1.Create, "Deployment", deployment("web", "db"), nil), secret, policy)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("Deployment web consumes the Secret (volume) as ServiceAccount web"))
+
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "apps"},
+				Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{Spec: podSpec("", "db")},
+				}}},
+			}
+			resp = handle(request(admissionv1.Create, "CronJob", cronJob, nil), secret, policy)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("CronJob backup consumes the Secret (volume) as ServiceAccount default"))
+		})
+
+		It("Should not block updates of workloads that already consumed the Secret", func() {
+			old := deployment("web", "db")
+			updated := deployment("web", "db")
+			replicas := int32(5)
+			updated.Spec.Replicas = &replicas
+			Expect(handle(request(admissionv1.Update, "Deployment", updated, old), secret, policy).Allowed).To(BeTrue())
+
+			Expect(handle(request(admissionv1.Update, "Deployment", updated, deployment("api", "db")), secret, policy).Allowed).To(BeFalse())
+		})
+
+		It("Should check workloads admitted before their Secret when the Secret is created", func() {
+			web := deployment("web", "db")
+			Expect(handle(request(admissionv1.Create, "Deployment", web, nil), policy).Allowed).To(BeTrue())
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "apps"}, Spec: podSpec("worker", "db")}
+			validator := &SecretValidator{
+				Client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(policy, web, pod).Build(),
+				Decoder: admission.NewDecoder(scheme.Scheme),
+			}
+			resp := validator.Handle(ctx, newSecretRequest(secret))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("Deployment web consumes the Secret (volume) as ServiceAccount web"))
+			Expect(resp.Result.Message).To(ContainSubstring("Pod worker consumes the Secret (volume) as ServiceAccount worker"))
+
+			req := newSecretRequest(secret)
+			req.Operation = admissionv1.Update
+			Expect(validator.Handle(ctx, req).Allowed).To(BeTrue())
+		})
+	})
+
+	Context("When applying enforcement actions and exceptions", func() {
+		It("Should warn for warn policies and ignore audit policies", func() {
+			policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionWarn
+			resp := handle(request(admissionv1.Create, "Deployment", deployment("web", "db"), nil), secret, policy)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ConsistOf(ContainSubstring("Secret db: SecretPolicy apps/db-access: [high] accessRules.consumers")))
+
+			policy.Spec.EnforcementAction = compliancev1alpha1.EnforcementActionAudit
+			resp = handle(request(admissionv1.Create, "Deployment", deployment("web", "db"), nil), secret, policy)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(BeEmpty())
+		})
+
+		It("Should admit consumers waived by an exception", func() {
+			exception := &compliancev1alpha1.SecretPolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "apps"},
+				Spec: compliancev1alpha1.SecretPolicyExceptionSpec{
+					PolicyRef:      compliancev1alpha1.PolicyReference{Name: "db-access"},
+					Rules:          []string{"accessRules.consumers"},
+					SecretSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					ExpiresAt:      metav1.NewTime(time.Now().Add(time.Hour)),
+					Justification:  "web moves to the api ServiceAccount",
+				},
+			}
+			resp := handle(request(admissionv1.Create, "Deployment", deployment("web", "db"), nil), secret, policy, exception)
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ConsistOf(ContainSubstring("waived by SecretPolicyException apps/migration")))
+		})
+	})
+
+	It("Should count decisions by kind", func() {
+		denied := testutil.ToFloat64(metrics.WorkloadAdmissionDecisions.WithLabelValues("Deployment", "CREATE", metrics.DecisionDenied))
+		handle(request(admissionv1.Create, "Deployment", deployment("web", "db"), nil), secret, policy)
+		Expect(testutil.ToFloat64(metrics.WorkloadAdmissionDecisions.WithLabelValues("Deployment", "CREATE", metrics.DecisionDenied))).
+			To(Equal(denied + 1))
+	})
+})